@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storyblok-cli-ai/internal/filecache"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the overlay file cache",
+	Long:  "Manage the content-addressable file cache under ~/.storyblok-ai-cli/cache that `create` consults to avoid rewriting unchanged overlay files.",
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached blobs older than --days",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCachePrune(cmd); err != nil {
+			printStructuredError(cmd, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().Int("days", 30, "Remove cached blobs last written more than this many days ago")
+	cacheCmd.AddCommand(cachePruneCmd)
+}
+
+func runCachePrune(cmd *cobra.Command) error {
+	days, _ := cmd.Flags().GetInt("days")
+	c, err := filecache.New()
+	if err != nil {
+		return fmt.Errorf("open cache: %w", err)
+	}
+	removed, err := c.Prune(time.Duration(days) * 24 * time.Hour)
+	if err != nil {
+		return fmt.Errorf("prune cache: %w", err)
+	}
+	fmt.Printf("Pruned %d cached blob(s) older than %d day(s).\n", removed, days)
+	return nil
+}
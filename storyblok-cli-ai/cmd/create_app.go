@@ -3,13 +3,13 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,11 +17,18 @@ import (
 	"strings"
 	"time"
 
-	"github.com/AlecAivazis/survey/v2"
-	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 
+	"storyblok-cli-ai/internal/backend"
+	"storyblok-cli-ai/internal/deps"
+	cerrors "storyblok-cli-ai/internal/errors"
+	"storyblok-cli-ai/internal/eventlog"
+	"storyblok-cli-ai/internal/filecache"
+	"storyblok-cli-ai/internal/formatters"
+	"storyblok-cli-ai/internal/pathguard"
 	"storyblok-cli-ai/internal/scaffold"
+	"storyblok-cli-ai/internal/scaffold/disk"
+	"storyblok-cli-ai/internal/session"
 )
 
 var createAppCmd = &cobra.Command{
@@ -29,17 +36,38 @@ var createAppCmd = &cobra.Command{
 	Short: "Scaffold a React + Storyblok app (AI-powered)",
 	Long:  "Interactive wizard that scaffolds a React + Tailwind app integrated with Storyblok using the AI backend.",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := runCreateWizard(cmd); err != nil {
-			printStructuredError(err)
+		if err := runCreateWizard(cmd, nil, nil); err != nil {
+			printStructuredError(cmd, err)
 			os.Exit(1)
 		}
 	},
 }
 
 func init() {
-	createAppCmd.Flags().StringP("output", "o", "", "Output folder for the generated project (default: ./<app_name>)")
+	createAppCmd.Flags().StringP("output", "o", "", "Output destination for the generated project: a local folder (default: ./<app_name>), or a disk.Disk URL — \"tar:-\"/\"tar:out.tar\", \"ftp://\", \"sftp://\", \"s3://\" — to export the scaffold there once generation finishes")
+	createAppCmd.Flags().String("resume", "", "Resume a previously interrupted create session by its session ID, reusing its temp dir and completed files instead of restarting the wizard")
+	createAppCmd.Flags().String("resume-dir", "", "Resume directly from a stream's tmpDir and its .scaffold-journal.jsonl, without needing the session's central checkpoint (e.g. after copying the tmpDir to another machine)")
+	createAppCmd.Flags().String("stream-format", "ndjson", `Streaming framing for /generate/stream: "ndjson" or "sse" (use sse behind proxies that buffer chunked NDJSON)`)
+	createAppCmd.Flags().Bool("no-cache", false, "Disable the content-addressable overlay file cache (always rewrite files, even if unchanged)")
+	createAppCmd.Flags().String("backend-url", defaultBackendURL, `Backend root URL (for --backend-transport=http) or fixtures directory (for --backend-transport=file, e.g. file:///path/to/fixtures)`)
+	createAppCmd.Flags().String("backend-transport", "http", `Backend transport: "http" (default, talks to the FastAPI backend) or "file" (replay recorded fixtures for offline dev/tests)`)
+	createAppCmd.Flags().Bool("offline-deps", false, "Resolve new_dependencies from the bundled version snapshot instead of the npm registry (no network required)")
+	createAppCmd.Flags().Bool("no-pin", false, "Skip dependency pinning entirely, leaving \"*\" placeholders in package.json for new_dependencies")
+	createAppCmd.Flags().String("pin-strategy", "caret", `Semver range format for pinned dependencies: "caret" (^1.2.3, default), "exact" (1.2.3), or "range" (>=1.2.3)`)
+	createAppCmd.Flags().StringSlice("secret-field", nil, "Field id(s) (in addition to the token) whose recorded answer should be redacted in the session log, e.g. --secret-field cms_password")
+	createAppCmd.Flags().String("event-log", "", "Tee every file_start/file_chunk/file_complete/dependency/warning event received from the backend to this JSONL file, for `scaffold replay` and CI snapshot tests")
+	createAppCmd.Flags().String("log-format", "text", `slog handler for the wizard's structured log output: "text" (default) or "json"`)
+	createAppCmd.Flags().Bool("no-format", false, "Skip the formatter registry entirely, leaving generated files exactly as the backend streamed them")
+	createAppCmd.Flags().String("format-only", "", "Skip the wizard and backend entirely: run the formatter registry against an existing project directory and exit")
+	createAppCmd.Flags().Int64("max-file-bytes", 0, "Abort the stream if a single file's accumulated size exceeds this many bytes (0 = no limit)")
+	createAppCmd.Flags().Int64("max-total-bytes", 0, "Abort the stream if the session's total generated size exceeds this many bytes (0 = no limit)")
+	createAppCmd.Flags().String("pkg-manager", "", "Package manager used to install the Storyblok CLI (pnpm/yarn/bun/npm); defaults to $STORYBLOK_CLI_PKG_MANAGER or auto-detection from PATH")
+	createAppCmd.Flags().Bool("allow-version-mismatch", false, "Don't fail if the installed Storyblok CLI's version doesn't match the version this build was tested against")
 }
 
+// defaultBackendURL is the backend root used when --backend-url isn't set.
+const defaultBackendURL = "http://127.0.0.1:8000"
+
 // ---------------- Cache helpers ----------------
 
 func answersCachePath() string {
@@ -69,57 +97,6 @@ func saveCachedAnswers(m map[string]string) error {
 	return os.WriteFile(path, b, 0o600)
 }
 
-// ---------------- Network helper ----------------
-
-func callBackend(backendURL string, payload map[string]interface{}) (map[string]interface{}, error) {
-	body, _ := json.Marshal(payload)
-	req, err := http.NewRequest("POST", backendURL, bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{Timeout: 180 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	b, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("backend returned status %d: %s", resp.StatusCode, string(b))
-	}
-	var parsed map[string]interface{}
-	if err := json.Unmarshal(b, &parsed); err != nil {
-		return nil, fmt.Errorf("failed to parse backend response: %w", err)
-	}
-	return parsed, nil
-}
-
-// ---------------- Streaming helper ----------------
-
-// callBackendStream posts the payload to the /generate/stream endpoint and returns the http.Response (caller must close body)
-func callBackendStream(backendURL string, payload map[string]interface{}) (*http.Response, error) {
-	body, _ := json.Marshal(payload)
-	req, err := http.NewRequest("POST", backendURL, bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	// no timeout to allow long streams; use a client with a long timeout
-	client := &http.Client{Timeout: 0}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		// read body for error message
-		b, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		return nil, fmt.Errorf("backend returned status %d: %s", resp.StatusCode, string(b))
-	}
-	return resp, nil
-}
-
 // ---------------- Utilities ----------------
 
 func slugify(s string) string {
@@ -136,8 +113,16 @@ func slugify(s string) string {
 	return s
 }
 
-// promptFollowupsAndCollect prompts the user for each followup item and returns answers map[id]=value
-func promptFollowupsAndCollect(followups []map[string]interface{}) (map[string]string, error) {
+// normalizeQuestion lowercases and collapses whitespace so the same
+// question worded with different spacing/case is still recognized as a
+// repeat across followup rounds.
+func normalizeQuestion(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimSpace(s))), " ")
+}
+
+// promptFollowupsAndCollect prompts the user (via wio) for each followup
+// item and returns answers map[id]=value.
+func promptFollowupsAndCollect(wio wizardIO, round int, followups []map[string]interface{}) (map[string]string, error) {
 	cache := loadCachedAnswers()
 	answers := map[string]string{}
 
@@ -162,13 +147,11 @@ func promptFollowupsAndCollect(followups []map[string]interface{}) (map[string]s
 
 		var resp string
 		for {
-			if err := survey.AskOne(&survey.Input{
-				Message: question,
-				Default: defaultVal,
-			}, &resp); err != nil {
+			v, err := wio.AskInput(round, qid, question, defaultVal)
+			if err != nil {
 				return nil, err
 			}
-			resp = strings.TrimSpace(resp)
+			resp = strings.TrimSpace(v)
 			// enforce non-empty answer (since you want natural text)
 			if resp == "" {
 				fmt.Println("Please provide a non-empty answer.")
@@ -211,36 +194,188 @@ func readJSONLine(r *bufio.Reader) ([]byte, error) {
 	return bytes.TrimSpace(line), nil
 }
 
+// readStreamEvent reads the next event's JSON payload from r, honoring
+// streamFormat. For "ndjson" it's just readJSONLine. For "sse" it collects
+// "data:" lines up to the next blank line (concatenating multi-line data,
+// per the SSE spec) and also returns the event's "id:" field, if the
+// backend sent one, so the caller can treat it as the resume checkpoint.
+func readStreamEvent(r *bufio.Reader, streamFormat string) (data []byte, id string, err error) {
+	if streamFormat != "sse" {
+		line, err := readJSONLine(r)
+		return line, "", err
+	}
+
+	sawAny := false
+	sawData := false
+	for {
+		line, rerr := r.ReadBytes('\n')
+		if rerr != nil {
+			if rerr == io.EOF && sawAny {
+				break
+			}
+			return nil, id, rerr
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if sawAny {
+				break
+			}
+			continue
+		}
+		sawAny = true
+		switch {
+		case bytes.HasPrefix(line, []byte("data:")):
+			if sawData {
+				data = append(data, '\n')
+			}
+			sawData = true
+			data = append(data, bytes.TrimPrefix(bytes.TrimPrefix(line, []byte("data:")), []byte(" "))...)
+		case bytes.HasPrefix(line, []byte("id:")):
+			id = strings.TrimSpace(strings.TrimPrefix(string(line), "id:"))
+		}
+	}
+	return data, id, nil
+}
+
 // ---------------- Main wizard ----------------
 var qResp map[string]interface{}
 
-func runCreateWizard(cmd *cobra.Command) error {
-	// 1) Single freeform prompt + token prompt
-	var description string
-	var token string
+// runCreateWizard drives the create wizard. presetAnswers and overrideBk are
+// nil for a normal interactive run; `replay` passes a prior session's
+// recorded answers (and, for --dry-run, a backend.Client that hands back
+// that session's recorded responses instead of calling a live backend) so
+// the same code path reproduces a shared session deterministically.
+func runCreateWizard(cmd *cobra.Command, presetAnswers map[string]string, overrideBk backend.Client) error {
+	streamFormat, _ := cmd.Flags().GetString("stream-format")
+	if streamFormat != "sse" {
+		streamFormat = "ndjson"
+	}
+
+	logFormat, _ := cmd.Flags().GetString("log-format")
+	ctx := eventlog.WithLogger(context.Background(), eventlog.NewLogger(logFormat))
+
+	// --format-only skips the wizard and backend entirely: it reuses the
+	// formatter registry against an existing project directory, which is
+	// handy after editing ~/.storyblok-cli/formatters.yaml or for offline
+	// re-formatting of a project generated with --no-format.
+	if formatOnlyDir, _ := cmd.Flags().GetString("format-only"); formatOnlyDir != "" {
+		return runFormatOnly(ctx, formatOnlyDir)
+	}
 
-	if err := survey.AskOne(&survey.Input{
-		Message: "What would you like to create?",
-		Help:    "Describe purpose, pages, features, and visual style. Be as natural-language as you like.",
-	}, &description); err != nil {
+	pkgManager, _ := cmd.Flags().GetString("pkg-manager")
+	allowVersionMismatch, _ := cmd.Flags().GetBool("allow-version-mismatch")
+	dm := &scaffold.DependencyManager{
+		In:                   os.Stdin,
+		Out:                  os.Stdout,
+		AssumeYes:            assumeYes(cmd),
+		PackageManager:       pkgManager,
+		AllowVersionMismatch: allowVersionMismatch,
+	}
+	if err := dm.Ensure(); err != nil {
+		return fmt.Errorf("ensure storyblok CLI: %w", err)
+	}
+
+	var evLog *eventlog.Recorder
+	if eventLogPath, _ := cmd.Flags().GetString("event-log"); eventLogPath != "" {
+		var everr error
+		evLog, everr = eventlog.Open(eventLogPath)
+		if everr != nil {
+			fmt.Fprintf(os.Stderr, "warning: event log unavailable: %v\n", everr)
+		} else {
+			defer evLog.Close()
+			fmt.Printf("Event log: %s (replay it with `storyblok-cli-ai scaffold replay --event-log %s --out <dir>`)\n", eventLogPath, eventLogPath)
+		}
+	}
+
+	var bk backend.Client
+	if overrideBk != nil {
+		bk = overrideBk
+	} else {
+		backendURLFlag, _ := cmd.Flags().GetString("backend-url")
+		backendTransport, _ := cmd.Flags().GetString("backend-transport")
+		var err error
+		bk, err = backend.New(backendURLFlag, backendTransport)
+		if err != nil {
+			return fmt.Errorf("set up backend client: %w", err)
+		}
+	}
+
+	sessionID := newSessionID()
+	secretFields, _ := cmd.Flags().GetStringSlice("secret-field")
+	rec, err := session.New(sessionID, secretFields)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: session log unavailable: %v\n", err)
+	} else {
+		defer rec.Close()
+		if path, perr := session.Path(sessionID); perr == nil {
+			fmt.Printf("Session log: %s (share it with `storyblok-cli-ai replay %s` to reproduce a bug)\n", path, sessionID)
+		}
+	}
+
+	var wio wizardIO
+	if presetAnswers != nil {
+		wio = &replayWizardIO{answers: presetAnswers, rec: rec}
+	} else {
+		wio = &liveWizardIO{rec: rec}
+	}
+
+	// --resume skips the whole wizard (prompts, storyblok create-demo run,
+	// overlay, iterative question rounds) and picks the streaming loop back
+	// up with the session's persisted tmpDir, payload and completedFiles.
+	if resumeSessionID, _ := cmd.Flags().GetString("resume"); resumeSessionID != "" {
+		cp, err := loadStreamCheckpoint(resumeSessionID)
+		if err != nil {
+			return fmt.Errorf("resume session %q: %w", resumeSessionID, err)
+		}
+		fmt.Printf("Resuming session %s (%d file(s) already completed)...\n", cp.SessionID, len(cp.CompletedFiles))
+		return runStreamingRounds(ctx, cmd, rec, evLog, wio, bk, cp.SessionID, streamFormat, cp.AbsTarget, cp.TmpDir, cp.Payload, cp.CompletedFiles, cp.LastCheckpoint, map[string]string{}, &[]string{}, nil, &[]deps.Suggestion{})
+	}
+
+	// --resume-dir is the tmpDir-local counterpart to --resume: it rebuilds
+	// state purely from that directory's .scaffold-journal.jsonl instead of
+	// the central ~/.storyblok-ai-cli/streams checkpoint, so a tmpDir copied
+	// elsewhere (or left behind after the checkpoint dir was cleared) is
+	// still resumable on its own.
+	if resumeDir, _ := cmd.Flags().GetString("resume-dir"); resumeDir != "" {
+		absResumeDir, err := filepath.Abs(resumeDir)
+		if err != nil {
+			return fmt.Errorf("invalid --resume-dir path: %w", err)
+		}
+		events, err := loadJournal(absResumeDir)
+		if err != nil {
+			return fmt.Errorf("resume from %q: %w", absResumeDir, err)
+		}
+		st, err := rebuildJournalState(absResumeDir, events)
+		if err != nil {
+			return fmt.Errorf("resume from %q: %w", absResumeDir, err)
+		}
+		if offsets := journalResumeOffsets(absResumeDir, st.InProgress); len(offsets) > 0 {
+			if opts, ok := st.Payload["options"].(map[string]interface{}); ok {
+				opts["resume_offsets"] = offsets
+			}
+			fmt.Printf("Truncation guard: %d in-progress file(s), resuming at their current byte length: %v\n", len(offsets), offsets)
+		}
+		fmt.Printf("Resuming from journal %s (%d file(s) already completed)...\n", filepath.Join(absResumeDir, journalFileName), len(st.CompletedFiles))
+		return runStreamingRounds(ctx, cmd, rec, evLog, wio, bk, st.SessionID, streamFormat, st.AbsTarget, absResumeDir, st.Payload, st.CompletedFiles, st.LastCheckpoint, map[string]string{}, &[]string{}, nil, &[]deps.Suggestion{})
+	}
+
+	// 1) Single freeform prompt + token prompt
+	description, err := wio.AskInput(0, "description", "What would you like to create?", "")
+	if err != nil {
 		return fmt.Errorf("prompt aborted: %w", err)
 	}
 	description = strings.TrimSpace(description)
 
-	if err := survey.AskOne(&survey.Input{
-		Message: "Storyblok API token (optional, will be written to .env if provided):",
-	}, &token); err != nil {
+	token, err := wio.AskInput(0, "token", "Storyblok API token (optional, will be written to .env if provided):", "")
+	if err != nil {
 		return fmt.Errorf("token prompt aborted: %w", err)
 	}
 	token = strings.TrimSpace(token)
 
 	// 2) Determine app name (slugify description). Allow user to edit name before proceeding.
 	defaultName := slugify(description)
-	var appName string
-	if err := survey.AskOne(&survey.Input{
-		Message: "Project name:",
-		Default: defaultName,
-	}, &appName); err != nil {
+	appName, err := wio.AskInput(0, "app_name", "Project name:", defaultName)
+	if err != nil {
 		return fmt.Errorf("project name prompt aborted: %w", err)
 	}
 	appName = strings.TrimSpace(appName)
@@ -248,12 +383,29 @@ func runCreateWizard(cmd *cobra.Command) error {
 		appName = defaultName
 	}
 
-	// 3) Determine output dir (flag override allowed)
+	// 3) Determine output destination (flag override allowed). A plain path
+	// (the common case, unchanged from before) is used directly as the
+	// project dir. A disk.Disk URL ("tar:-", "ftp://", "sftp://", "s3://")
+	// instead builds the scaffold in a local staging dir and exports it to
+	// that destination once generation finishes (exportStagingAndCleanup) —
+	// scaffolding itself always needs a real local directory, since the
+	// Storyblok CLI and file formatters only know how to run against one.
 	outputFlag, _ := cmd.Flags().GetString("output")
 	var targetDir string
-	if outputFlag != "" {
+	var exportDisk disk.Disk
+	switch {
+	case outputFlag != "" && looksLikeDiskURL(outputFlag):
+		exportDisk, err = disk.New(outputFlag)
+		if err != nil {
+			return fmt.Errorf("set up --output destination: %w", err)
+		}
+		targetDir, err = os.MkdirTemp("", "ai_scaffold_stage_*")
+		if err != nil {
+			return fmt.Errorf("create local staging dir: %w", err)
+		}
+	case outputFlag != "":
 		targetDir = outputFlag
-	} else {
+	default:
 		targetDir = "./" + appName
 	}
 	absTarget, err := filepath.Abs(targetDir)
@@ -276,44 +428,26 @@ func runCreateWizard(cmd *cobra.Command) error {
 		},
 	}
 
-	backendStreamURL := "http://127.0.0.1:8000/generate/stream"
-	backendURL := "http://127.0.0.1:8000/generate/"
-
 	// ask user which Storyblok demo framework to use (restricted list)
-	var chosenFramework string
-	frameworkPrompt := &survey.Select{
-		Message: "Choose a base framework for the Storyblok demo scaffold:",
-		Options: allowedFrameworks,
-		Default: allowedFrameworks[0],
-	}
-	if err := survey.AskOne(frameworkPrompt, &chosenFramework); err != nil {
+	chosenFramework, err := wio.AskSelect(0, "framework", "Choose a base framework for the Storyblok demo scaffold:", allowedFrameworks, allowedFrameworks[0])
+	if err != nil {
 		return fmt.Errorf("framework prompt aborted: %w", err)
 	}
 
 	// ask package manager (npm or yarn)
-	var chosenPM string
-	pmPrompt := &survey.Select{
-		Message: "Choose package manager for the scaffold:",
-		Options: []string{"npm", "yarn"},
-		Default: "npm",
-	}
-	if err := survey.AskOne(pmPrompt, &chosenPM); err != nil {
+	chosenPM, err := wio.AskSelect(0, "package_manager", "Choose package manager for the scaffold:", []string{"npm", "yarn"}, "npm")
+	if err != nil {
 		return fmt.Errorf("package manager prompt aborted: %w", err)
 	}
 
 	// Region selection (replace previous freeform region input)
-	var regionChoice string
-	regionPrompt := &survey.Select{
-		Message: "Space Region (optional, EU is used by default):",
-		Options: []string{
-			"EU - Europe",
-			"US - United States",
-			"CN - China",
-			"CA - Canada",
-		},
-		Default: "EU - Europe",
-	}
-	if err := survey.AskOne(regionPrompt, &regionChoice); err != nil {
+	regionChoice, err := wio.AskSelect(0, "region", "Space Region (optional, EU is used by default):", []string{
+		"EU - Europe",
+		"US - United States",
+		"CN - China",
+		"CA - Canada",
+	}, "EU - Europe")
+	if err != nil {
 		return fmt.Errorf("region prompt aborted: %w", err)
 	}
 
@@ -367,13 +501,14 @@ func runCreateWizard(cmd *cobra.Command) error {
 		fmt.Println("Failed to marshal payload for debug:", err)
 	}
 
-	// Call overlay endpoint (make sure your backend has /generate/overlay)
-	backendOverlayURL := "http://127.0.0.1:8000/generate/overlay"
 	fmt.Println("Sending scaffold to overlay backend for customization...")
-	overlayResp, err := callOverlayBackend(backendOverlayURL, overlayPayload)
+	rec.Record(session.EventBackendRequest, 0, map[string]interface{}{"method": "overlay", "payload": overlayPayload})
+	overlayResp, err := bk.Overlay(overlayPayload)
 	if err != nil {
+		rec.Record(session.EventError, 0, map[string]interface{}{"method": "overlay", "error": err.Error()})
 		return fmt.Errorf("overlay backend failed: %w", err)
 	}
+	rec.Record(session.EventBackendResponse, 0, map[string]interface{}{"method": "overlay", "response": overlayResp})
 
 	// Parse response: expect {"files": [...], "new_dependencies": [...], "warnings": [...]}
 	var changedFilesRaw []map[string]interface{}
@@ -399,12 +534,61 @@ func runCreateWizard(cmd *cobra.Command) error {
 		}
 	}
 
-	// Apply overlay into the scaffold workspace (absTarget). This writes changed files and merges new deps into package.json.
-	written, err := applyOverlayToScaffold(absTarget, changedFilesRaw, newDeps)
+	// Apply overlay into the scaffold workspace (absTarget). This writes changed files; dependency pinning happens below.
+	var overlayCache *filecache.Cache
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+		if c, cerr := filecache.New(); cerr == nil {
+			overlayCache = c
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: overlay file cache unavailable: %v\n", cerr)
+		}
+	}
+	written, err := applyOverlayToScaffold(absTarget, changedFilesRaw, overlayCache)
 	if err != nil {
 		return fmt.Errorf("applying overlay to scaffold failed: %w", err)
 	}
 	fmt.Printf("Applied overlay: %d files written/updated.\n", len(written))
+	rec.Record(session.EventOverlayApply, 0, map[string]interface{}{"written": written, "count": len(written)})
+
+	var pinnedVersions map[string]deps.Pinned
+	if len(newDeps) > 0 {
+		if noPin, _ := cmd.Flags().GetBool("no-pin"); noPin {
+			fmt.Println("Skipping dependency pinning (--no-pin); package.json keeps \"*\" placeholders for:", strings.Join(newDeps, ", "))
+		} else {
+			offlineDeps, _ := cmd.Flags().GetBool("offline-deps")
+			pinStrategyFlag, _ := cmd.Flags().GetString("pin-strategy")
+			pkgPath, perr := pathguard.SanitizeRelPath(absTarget, "package.json", pathguard.DefaultDenyGlobs)
+			if perr != nil {
+				return fmt.Errorf("resolve package.json path: %w", perr)
+			}
+
+			// Prefer what the scaffold's own package manager would actually
+			// resolve (via a lockfile-only install in a sandbox copy) over
+			// a bare registry "latest" lookup; Pin falls back to the
+			// registry/snapshot for anything this pass couldn't cover.
+			resolved, lockErr := deps.LockfileResolve(absTarget, chosenPM, newDeps)
+			if lockErr != nil {
+				fmt.Fprintf(os.Stderr, "warning: lockfile resolution failed (%v); falling back to registry lookups\n", lockErr)
+				resolved = nil
+			}
+
+			pinResult, err := deps.Pin(pkgPath, newDeps, deps.Options{
+				Offline:     offlineDeps,
+				PinStrategy: deps.PinStrategy(pinStrategyFlag),
+				Resolved:    resolved,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: dependency pinning failed (%v); package.json still has unpinned placeholders\n", err)
+			} else {
+				pinnedVersions = pinResult.ByName()
+				fmt.Printf("Running %s install to update the lockfile...\n", chosenPM)
+				if err := deps.Install(absTarget, chosenPM); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: %v; run %s install manually\n", err, chosenPM)
+				}
+				fmt.Println("Pinned dependencies:", pinResult.Summary())
+			}
+		}
+	}
 
 	// Collect final files from absTarget (now include package.json)
 	finalFiles := []scaffold.FileOut{}
@@ -450,30 +634,18 @@ func runCreateWizard(cmd *cobra.Command) error {
 	}
 
 	fmt.Println("Storyblok scaffold + overlay applied. Project created at:", absTarget)
-	if len(newDeps) > 0 {
-		fmt.Println("\n⚠️  Note: the backend suggested new dependencies (names only). They were merged into package.json as placeholders.")
-		fmt.Println("Run `npm install` (or your package manager) to install and pin them, or use the CLI's dependency pinning step.")
-	}
 
 	// proceed to the rest of the flow (followups / generation pipeline / streaming) as before
 
 	// --- before the iterative rounds, declare helpers/state ---
 	questionTexts := map[string]string{} // id -> question text
-	currentRound := 0
 	// --- Iterative question-generation rounds with smart stopping & UI preview ---
 	maxFollowupRounds := 2 // number of rounds
 	roundQuestions := 5    // requested per round
 	urgencyThreshold := 0.25
 	askedQuestions := []string{} // normalized previously asked question texts
 
-	// helper to normalize
-	normalize := func(s string) string {
-		// lower + collapse whitespace
-		return strings.Join(strings.Fields(strings.ToLower(strings.TrimSpace(s))), " ")
-	}
-
 	for round := 1; round <= maxFollowupRounds; round++ {
-		currentRound = round
 		// UI preview: show previous followup answers to the user
 		userAns, _ := payload["user_answers"].(map[string]interface{})
 
@@ -492,15 +664,17 @@ func runCreateWizard(cmd *cobra.Command) error {
 			},
 		}
 
-		qResp, err := callBackend(backendURL+"questions", qPayload)
+		rec.Record(session.EventBackendRequest, round, map[string]interface{}{"method": "questions", "payload": qPayload})
+		qResp, err := bk.Questions(qPayload)
 		if err != nil {
+			rec.Record(session.EventError, round, map[string]interface{}{"method": "questions", "error": err.Error()})
 			fmt.Fprintf(os.Stderr, "warning: question-generation failed (round %d): %v\n", round, err)
 			// fallback to generic prompt only on first round
 			if round == 1 {
 				initialFollowups := []map[string]interface{}{
 					{"id": "", "question": "Briefly describe the key requirements (pages, main features, visual style):", "type": "text", "default": ""},
 				}
-				ansMap, err := promptFollowupsAndCollect(initialFollowups)
+				ansMap, err := promptFollowupsAndCollect(wio, round, initialFollowups)
 				if err != nil {
 					return fmt.Errorf("aborted while answering initial requirements: %w", err)
 				}
@@ -522,6 +696,7 @@ func runCreateWizard(cmd *cobra.Command) error {
 			// if backend failed mid-rounds, just break and proceed
 			break
 		}
+		rec.Record(session.EventBackendResponse, round, map[string]interface{}{"method": "questions", "response": qResp})
 
 		// Parse followups — accept both objects and strings
 		currentFollowups := []map[string]interface{}{}
@@ -562,7 +737,7 @@ func runCreateWizard(cmd *cobra.Command) error {
 		filteredFollowups := []map[string]interface{}{}
 		for _, fu := range currentFollowups {
 			qtxt, _ := fu["question"].(string)
-			n := normalize(qtxt)
+			n := normalizeQuestion(qtxt)
 			// skip duplicates
 			already := false
 			for _, aq := range askedQuestions {
@@ -604,11 +779,11 @@ func runCreateWizard(cmd *cobra.Command) error {
 		// record askedQuestions so future rounds don't repeat
 		for _, fu := range filteredFollowups {
 			if qtxt, ok := fu["question"].(string); ok {
-				askedQuestions = append(askedQuestions, normalize(qtxt))
+				askedQuestions = append(askedQuestions, normalizeQuestion(qtxt))
 			}
 		}
 
-		ansMap, err := promptFollowupsAndCollect(toPrompt)
+		ansMap, err := promptFollowupsAndCollect(wio, round, toPrompt)
 		if err != nil {
 			return fmt.Errorf("aborted while answering followups (round %d): %w", round, err)
 		}
@@ -632,17 +807,84 @@ func runCreateWizard(cmd *cobra.Command) error {
 	}
 
 	// 5) followup loop (streaming)
+	streamSessionID := newSessionID()
+	var depSuggestions []deps.Suggestion
+	streamErr := runStreamingRounds(ctx, cmd, rec, evLog, wio, bk, streamSessionID, streamFormat, absTarget, "", payload, nil, "", questionTexts, &askedQuestions, exportDisk, &depSuggestions)
+
+	// Cross-check the backend's streamed dependency suggestions against
+	// what the overlay pinning pass above actually resolved: a
+	// high-confidence disagreement usually means the backend's training
+	// data is stale against the registry or lockfile.
+	if len(pinnedVersions) > 0 && len(depSuggestions) > 0 {
+		for _, w := range deps.CheckSuggestions(depSuggestions, pinnedVersions, 0.7) {
+			fmt.Fprintln(os.Stderr, "warning:", w)
+		}
+	}
+	return streamErr
+}
+
+// runStreamingRounds drives the /generate/stream followup/file loop. It's
+// factored out of runCreateWizard so --resume can re-enter it directly
+// with a previously persisted tmpDir/payload/completedFiles instead of
+// replaying the wizard's prompts. tmpDir and lastCheckpoint may be empty
+// (fresh session); a fresh tmpDir is created on first use. exportDisk is
+// non-nil only when --output pointed at a disk.Disk URL; on success its
+// contents are republished there instead of left in absTarget's local
+// staging dir. --resume/--resume-dir always pass nil, so a resumed run
+// finishes in its original local staging dir rather than re-resolving
+// --output.
+func runStreamingRounds(ctx context.Context, cmd *cobra.Command, rec *session.Recorder, evLog *eventlog.Recorder, wio wizardIO, bk backend.Client, sessionID, streamFormat, absTarget, tmpDir string, payload map[string]interface{}, completedFiles []scaffold.FileOut, lastCheckpoint string, questionTexts map[string]string, askedQuestions *[]string, exportDisk disk.Disk, depSuggestions *[]deps.Suggestion) error {
+	currentRound := 0
 	maxRounds := 20
+
+	// stagingDisk is the local Disk every generated file lands on as it
+	// streams in (absTarget is always a real local dir, whether it's the
+	// user's final project folder or a temp staging dir for a non-local
+	// --output); exportDisk, if set, republishes it elsewhere afterward.
+	stagingDisk, err := disk.New(absTarget)
+	if err != nil {
+		return fmt.Errorf("open staging dir %q: %w", absTarget, err)
+	}
+
+	// jf is the tmpDir-local journal (see stream_journal.go), opened once
+	// tmpDir exists and reused across rounds/reconnects so --resume-dir can
+	// rebuild state from tmpDir alone. A nil jf (journal unavailable) just
+	// disables this path; the central streamCheckpoint still works.
+	var jf *os.File
+	defer func() {
+		if jf != nil {
+			jf.Close()
+		}
+	}()
+	ensureJournal := func() {
+		if jf != nil || tmpDir == "" {
+			return
+		}
+		f, err := openJournal(tmpDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: stream journal unavailable: %v\n", err)
+			return
+		}
+		jf = f
+	}
+	ensureJournal()
+
 	for round := 1; round < maxRounds; round++ {
 		currentRound = round
-		// call streaming endpoint to get progress + files
-		resp, err := callBackendStream(backendStreamURL, payload)
+		// call streaming endpoint to get progress + files, reconnecting
+		// with exponential backoff on transport-level failures before
+		// falling back to the non-streaming endpoint.
+		rec.Record(session.EventBackendRequest, round, map[string]interface{}{"method": "generate_stream", "payload": payload})
+		body, err := connectStreamWithRetry(bk, payload, streamFormat, lastCheckpoint)
 		if err != nil {
 			// fallback to non-streaming behavior (older backend)
-			respMap, err2 := callBackend(backendURL, payload)
+			rec.Record(session.EventBackendRequest, round, map[string]interface{}{"method": "generate", "payload": payload})
+			respMap, err2 := bk.Generate(payload)
 			if err2 != nil {
+				rec.Record(session.EventError, round, map[string]interface{}{"method": "generate", "error": err2.Error()})
 				return fmt.Errorf("call backend (stream failed, fallback failed): %v / %v", err, err2)
 			}
+			rec.Record(session.EventBackendResponse, round, map[string]interface{}{"method": "generate", "response": respMap})
 			// same behavior as before
 			var followups []map[string]interface{}
 			if fRaw, ok := respMap["followups"]; ok {
@@ -662,7 +904,15 @@ func runCreateWizard(cmd *cobra.Command) error {
 				if len(files) == 0 {
 					return fmt.Errorf("backend returned no files and no followups")
 				}
-				fmt.Println("Project created successfully at:", absTarget)
+				if exportDisk != nil {
+					outputFlag, _ := cmd.Flags().GetString("output")
+					if err := exportStagingAndCleanup(absTarget, exportDisk); err != nil {
+						return err
+					}
+					fmt.Println("Project created successfully and exported to:", outputFlag)
+				} else {
+					fmt.Println("Project created successfully at:", absTarget)
+				}
 				fmt.Println("\n⚠️  Security note:")
 				fmt.Println("  - A .env file containing your Storyblok token may have been written to the project root.")
 				fmt.Println("  - Do NOT commit .env to source control. .gitignore includes .env by default.")
@@ -670,10 +920,13 @@ func runCreateWizard(cmd *cobra.Command) error {
 			}
 
 			// ask followups and continue
-			answersMap, err := promptFollowupsAndCollect(followups)
+			answersMap, err := promptFollowupsAndCollect(wio, round, followups)
 			if err != nil {
 				return fmt.Errorf("aborted while answering followups: %w", err)
 			}
+			for id, v := range answersMap {
+				appendJournal(jf, journalEvent{Type: journalFollowup, ID: id, Value: v})
+			}
 			userAns, _ := payload["user_answers"].(map[string]interface{})
 			if userAns == nil {
 				userAns = map[string]interface{}{}
@@ -701,19 +954,41 @@ func runCreateWizard(cmd *cobra.Command) error {
 		}
 
 		// Stream reader
-		reader := bufio.NewReader(resp.Body)
+		reader := bufio.NewReader(body)
 
-		// temp dir to store files as they stream
-		tmpDir, _ := os.MkdirTemp("", "ai_stream_*")
+		// temp dir to store files as they stream; reused across reconnects
+		// and rounds (and, on --resume, the prior session's tmpDir) so
+		// files already flushed to disk survive a dropped connection.
+		if tmpDir == "" {
+			tmpDir, err = os.MkdirTemp("", "ai_stream_*")
+			if err != nil {
+				return fmt.Errorf("create stream temp dir: %w", err)
+			}
+			ensureJournal()
+		}
 		defer os.RemoveAll(tmpDir)
-		// map path -> temp file path
-		tempFiles := map[string]string{}
-		// set to collect completed files for final atomic write
-		completedFiles := []scaffold.FileOut{}
 
-		// progress bar (indeterminate until finished)
-		var pb *progressbar.ProgressBar
-		generatedCount := 0
+		// state drives the file_start/file_chunk/file_complete/dependency/
+		// warning state machine shared with `scaffold replay` (see
+		// stream_events.go); tempFiles lives on it now instead of as a
+		// bare local map.
+		var reg *formatters.Registry
+		if noFormat, _ := cmd.Flags().GetBool("no-format"); !noFormat {
+			reg, err = formatters.Load()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: formatters config unavailable, using defaults: %v\n", err)
+				reg = formatters.DefaultRegistry()
+			}
+		}
+		maxFileBytes, _ := cmd.Flags().GetInt64("max-file-bytes")
+		maxTotalBytes, _ := cmd.Flags().GetInt64("max-total-bytes")
+		state := newStreamEventState(ctx, tmpDir, stagingDisk, jf, depSuggestions, reg, false, maxFileBytes, maxTotalBytes)
+		state.generatedCount = len(completedFiles)
+		state.completedFiles = completedFiles
+
+		if payloadJSON, merr := json.Marshal(payload); merr == nil {
+			appendJournal(jf, journalEvent{Type: journalSessionMeta, SessionID: sessionID, AbsTarget: absTarget, Payload: payloadJSON})
+		}
 
 		handleFollowups := func(followupsIface interface{}, round int) (bool, error) {
 			// convert to []map[string]interface{}
@@ -747,12 +1022,15 @@ func runCreateWizard(cmd *cobra.Command) error {
 			}
 
 			// close stream body and prompt user
-			_ = resp.Body.Close()
+			_ = body.Close()
 
-			ansMap, err := promptFollowupsAndCollect(out)
+			ansMap, err := promptFollowupsAndCollect(wio, round, out)
 			if err != nil {
 				return false, err
 			}
+			for id, v := range ansMap {
+				appendJournal(jf, journalEvent{Type: journalFollowup, ID: id, Value: v})
+			}
 
 			// attach answers and break to outer loop
 			userAns, _ := payload["user_answers"].(map[string]interface{})
@@ -772,7 +1050,7 @@ func runCreateWizard(cmd *cobra.Command) error {
 			// also add these asked question texts to askedQuestions so iterative rounds avoid repeats
 			for _, it := range out {
 				if qtxt, ok := it["question"].(string); ok {
-					askedQuestions = append(askedQuestions, normalize(qtxt))
+					*askedQuestions = append(*askedQuestions, normalizeQuestion(qtxt))
 				}
 			}
 
@@ -780,15 +1058,29 @@ func runCreateWizard(cmd *cobra.Command) error {
 		}
 
 		// read loop
+	readLoop:
 		for {
-			lineBytes, err := readJSONLine(reader)
+			lineBytes, eventID, err := readStreamEvent(reader, streamFormat)
 			if err != nil {
 				if err == io.EOF {
-					break
+					break readLoop
+				}
+				// Connection dropped mid-stream: reconnect with the last
+				// checkpoint so the backend skips files we already have,
+				// instead of aborting the whole wizard.
+				_ = body.Close()
+				body, err = connectStreamWithRetry(bk, payload, streamFormat, lastCheckpoint)
+				if err != nil {
+					return fmt.Errorf("stream interrupted and reconnect failed (resume later with --resume %s): %w", sessionID, err)
 				}
-				// close and return on other errors
-				_ = resp.Body.Close()
-				return fmt.Errorf("error reading stream: %w", err)
+				reader = bufio.NewReader(body)
+				continue
+			}
+			if eventID != "" {
+				lastCheckpoint = eventID
+			}
+			if len(lineBytes) == 0 {
+				continue
 			}
 			var ev map[string]interface{}
 			if err := json.Unmarshal(lineBytes, &ev); err != nil {
@@ -797,6 +1089,7 @@ func runCreateWizard(cmd *cobra.Command) error {
 			}
 			etype, _ := ev["event"].(string)
 			payloadEv := ev["payload"]
+			evLog.Record(etype, eventID, currentRound, payloadEv)
 
 			switch etype {
 			case "followups":
@@ -806,121 +1099,81 @@ func runCreateWizard(cmd *cobra.Command) error {
 					return fmt.Errorf("error while handling followups: %w", err)
 				}
 				if shouldContinue {
-					// break reading and restart outer followup loop
-					break
+					// body is already closed by handleFollowups; stop
+					// reading it and restart the outer followup loop
+					break readLoop
 				}
 			case "file_start":
 				m, _ := payloadEv.(map[string]interface{})
 				path, _ := m["path"].(string)
-				// create temp file to append chunks
-				tf := filepath.Join(tmpDir, strings.ReplaceAll(path, "/", "__"))
-				_ = os.MkdirAll(filepath.Dir(tf), 0o755)
-				// ensure file exists (trunc)
-				_ = os.WriteFile(tf, []byte(""), 0o644)
-				tempFiles[path] = tf
+				if serr := state.fileStart(path); serr != nil {
+					return serr
+				}
 			case "file_chunk":
 				m, _ := payloadEv.(map[string]interface{})
 				path, _ := m["path"].(string)
 				chunk, _ := m["chunk"].(string)
-				final, _ := m["final"].(bool)
-				tf, ok := tempFiles[path]
-				if !ok {
-					// create if not present
-					tf = filepath.Join(tmpDir, strings.ReplaceAll(path, "/", "__"))
-					_ = os.MkdirAll(filepath.Dir(tf), 0o755)
-					_ = os.WriteFile(tf, []byte(""), 0o644)
-					tempFiles[path] = tf
-				}
-				// append chunk
-				f, ferr := os.OpenFile(tf, os.O_APPEND|os.O_WRONLY, 0o644)
-				if ferr == nil {
-					_, _ = f.WriteString(chunk)
-					f.Close()
+				if serr := state.fileChunk(path, chunk); serr != nil {
+					return serr
 				}
-				_ = final // nothing now
 			case "dependency":
 				if m, ok := payloadEv.(map[string]interface{}); ok {
 					name, _ := m["name"].(string)
 					version, _ := m["version"].(string)
 					conf, _ := m["confidence"].(float64)
-					if version != "" {
-						fmt.Printf("Resolved: %s@%s (confidence %.2f)\n", name, version, conf)
-					} else {
-						// print candidate summary if available
-						if cands, ok := m["candidates"].([]interface{}); ok && len(cands) > 0 {
-							fmt.Printf("Dependency not found: %s — suggested: %v\n", name, cands)
-						} else {
-							fmt.Printf("Dependency not found: %s\n", name)
-						}
-					}
+					candidates, _ := m["candidates"].([]interface{})
+					state.dependency(name, version, conf, candidates)
 				}
 
 			case "file_complete":
 				m, _ := payloadEv.(map[string]interface{})
 				path, _ := m["path"].(string)
-				tf, ok := tempFiles[path]
+				content, checkpoint, ok, ferr := state.fileComplete(path, eventID, lastCheckpoint)
+				if ferr != nil {
+					return ferr
+				}
 				if !ok {
 					// missing temp file; skip
 					continue
 				}
-
-				// read temp file into memory
-				contentBytes, _ := os.ReadFile(tf)
-				content := string(contentBytes)
-
-				// run formatter on the temp file (in place)
-				if err := runFormatterForFile(tf); err == nil {
-					// re-read file after formatting
-					if newBytes, err2 := os.ReadFile(tf); err2 == nil {
-						content = string(newBytes)
-					}
+				lastCheckpoint = checkpoint
+				completedFiles = state.completedFiles
+				rec.Record(session.EventFileWrite, currentRound, map[string]interface{}{"path": path, "bytes": len(content)})
+
+				// Persist a checkpoint after every file, so --resume can
+				// pick up here instead of replaying the wizard.
+				cp := &streamCheckpoint{
+					SessionID:      sessionID,
+					AbsTarget:      absTarget,
+					TmpDir:         tmpDir,
+					Payload:        payload,
+					CompletedFiles: completedFiles,
+					LastCheckpoint: lastCheckpoint,
 				}
-
-				completedFiles = append(completedFiles, scaffold.FileOut{
-					Path:    path,
-					Content: content,
-				})
-				generatedCount += 1
-
-				// initialize progress bar if needed
-				if pb == nil {
-					pb = progressbar.NewOptions(-1,
-						progressbar.OptionSetDescription("Generating files"),
-						progressbar.OptionShowCount(),
-						progressbar.OptionSpinnerType(14),
-					)
+				if err := saveStreamCheckpoint(cp); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to save resume checkpoint: %v\n", err)
 				}
-				_ = pb.Add(1)
 
 			case "warning":
-				// print warnings
-				if s, ok := payloadEv.(string); ok {
-					fmt.Printf("\nWARNING: %s\n", s)
-				} else {
-					bs, _ := json.Marshal(payloadEv)
-					fmt.Printf("\nWARNING: %s\n", string(bs))
-				}
+				state.warning(payloadEv)
 			case "done":
-				// final event; break reading
-				// finish progress bar if exists
-				if pb != nil {
-					_ = pb.Finish()
-				}
-				break
+				// final event; stop reading
+				state.finish()
+				break readLoop
 			default:
-				// ignore other events (dependency/validation intentionally ignored)
+				// ignore other events (validation intentionally ignored)
 			}
 
 			// continue reading until done or followups
 		}
 
 		// ensure final newline for progress if progress bar not used
-		if pb == nil {
+		if state.pb == nil {
 			fmt.Printf("\n")
 		}
 
 		// close response body now
-		_ = resp.Body.Close()
+		_ = body.Close()
 
 		// If followups were delivered and we handled them, continue outer loop
 		// (we detect this because payload["user_answers"] will have updated followup_answers)
@@ -931,7 +1184,17 @@ func runCreateWizard(cmd *cobra.Command) error {
 			continue
 		}
 
-		fmt.Println("Project created successfully at:", absTarget)
+		_ = os.Remove(streamSessionPath(sessionID))
+
+		if exportDisk != nil {
+			outputFlag, _ := cmd.Flags().GetString("output")
+			if err := exportStagingAndCleanup(absTarget, exportDisk); err != nil {
+				return err
+			}
+			fmt.Println("Project created successfully and exported to:", outputFlag)
+		} else {
+			fmt.Println("Project created successfully at:", absTarget)
+		}
 
 		fmt.Println("\n⚠️  Security note:")
 		fmt.Println("  - A .env file containing your Storyblok token may have been written to the project root.")
@@ -939,51 +1202,28 @@ func runCreateWizard(cmd *cobra.Command) error {
 		return nil
 	}
 
-	return fmt.Errorf("maximum followup rounds reached (%d). Aborting", maxRounds)
+	return fmt.Errorf("maximum followup rounds reached (%d); resume with --resume %s", maxRounds, sessionID)
 }
 
 // ---------------- helpers ----------------
 
-func printStructuredError(err error) {
+func printStructuredError(cmd *cobra.Command, err error) {
 	type Out struct {
 		Error   string `json:"error"`
 		Message string `json:"message,omitempty"`
+		Stack   string `json:"stack,omitempty"`
 	}
 	out := Out{
 		Error:   "create-app-failed",
 		Message: err.Error(),
 	}
-	b, _ := json.MarshalIndent(out, "", "  ")
-	fmt.Fprintln(os.Stderr, string(b))
-}
-
-func runFormatterForFile(path string) error {
-	ext := strings.ToLower(filepath.Ext(path))
-	switch ext {
-	case ".ts", ".tsx", ".js", ".jsx", ".json", ".css", ".html", ".md":
-		if _, err := exec.LookPath("npx"); err == nil {
-			// prettier via npx; --yes so it doesn't prompt
-			cmd := exec.Command("npx", "--yes", "prettier", "--write", path)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
-		}
-	case ".py":
-		if _, err := exec.LookPath("black"); err == nil {
-			cmd := exec.Command("black", path)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
-		}
-	case ".go":
-		if _, err := exec.LookPath("gofmt"); err == nil {
-			cmd := exec.Command("gofmt", "-w", path)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		if trace, ok := cerrors.StackTrace(err); ok {
+			out.Stack = trace
 		}
 	}
-	return nil
+	b, _ := json.MarshalIndent(out, "", "  ")
+	fmt.Fprintln(os.Stderr, string(b))
 }
 
 // helper
@@ -1022,19 +1262,22 @@ func runStoryblokCreateAndCollect(framework, targetDir, token, packagemanager, r
 		return "", nil, fmt.Errorf("running storyblok create failed: %w", err)
 	}
 
-	// Walk the generated folder and collect files, excluding package.json and lockfiles and node_modules/.git
-	collected := []scaffold.FileOut{}
-	err := filepath.WalkDir(targetDir, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			// ignore problematic files but continue
-			return nil
-		}
-		rel, _ := filepath.Rel(targetDir, path)
+	// Walk the generated folder through the local staging Disk (see
+	// runCreateWizard) rather than raw filepath.WalkDir/os.ReadFile, so this
+	// collection step uses the same abstraction as every other scaffold
+	// write. Excludes package.json, lockfiles, and node_modules/.git.
+	d, err := disk.New(targetDir)
+	if err != nil {
+		return targetDir, nil, fmt.Errorf("open generated scaffold dir: %w", err)
+	}
+	reader, ok := d.(disk.Reader)
+	if !ok {
+		return targetDir, nil, fmt.Errorf("scaffold staging disk %q doesn't support reading files back", targetDir)
+	}
 
-		rel = filepath.ToSlash(rel)
-		// Skip directories we don't want to descend into
-		if d.IsDir() {
-			// skip node_modules and .git
+	collected := []scaffold.FileOut{}
+	err = d.WalkDir("", func(rel string, entry disk.Entry) error {
+		if entry.IsDir() {
 			if rel == "node_modules" || strings.HasPrefix(rel, "node_modules/") {
 				return filepath.SkipDir
 			}
@@ -1043,17 +1286,20 @@ func runStoryblokCreateAndCollect(framework, targetDir, token, packagemanager, r
 			}
 			return nil
 		}
-		// skip package.json and known lockfiles
-		base := filepath.Base(path)
+		base := filepath.Base(rel)
 		if base == "package.json" || base == "package-lock.json" || base == "yarn.lock" || base == "pnpm-lock.yaml" {
 			return nil
 		}
-		// read file
-		b, rerr := os.ReadFile(path)
+		rc, rerr := reader.Open(rel)
 		if rerr != nil {
 			// ignore read errors
 			return nil
 		}
+		defer rc.Close()
+		b, rerr := io.ReadAll(rc)
+		if rerr != nil {
+			return nil
+		}
 		collected = append(collected, scaffold.FileOut{Path: rel, Content: string(b)})
 		return nil
 	})
@@ -1065,42 +1311,25 @@ func runStoryblokCreateAndCollect(framework, targetDir, token, packagemanager, r
 	return targetDir, collected, nil
 }
 
-// callOverlayBackend posts the base scaffold to the backend overlay endpoint and returns parsed JSON.
-// backendOverlayURL should be full e.g. http://127.0.0.1:8000/generate/overlay
-// payload fields: user_answers, storyblok_schema, options, base_files
-func callOverlayBackend(backendOverlayURL string, payload map[string]interface{}) (map[string]interface{}, error) {
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("marshal overlay request: %w", err)
-	}
-	req, err := http.NewRequest("POST", backendOverlayURL, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{Timeout: 180 * time.Second}
-	resp, err := client.Do(req)
+// applyOverlayToScaffold writes the changed files returned by the backend
+// into scaffoldDir. Dependency pinning happens separately, via
+// internal/deps, once all files (including package.json) are in place.
+// It returns the files written so far; the first write error stops it and
+// is returned, while cache failures are only printed as warnings.
+func applyOverlayToScaffold(scaffoldDir string, changedFiles []map[string]interface{}, cache *filecache.Cache) ([]string, error) {
+	// The scaffold always builds in a local staging dir (see runCreateWizard),
+	// so this is always a localDisk; routing the actual write through it
+	// rather than a direct os.WriteFile keeps every scaffold write on the
+	// same Disk abstraction --output's export step reads back from.
+	d, err := disk.New(scaffoldDir)
 	if err != nil {
-		return nil, fmt.Errorf("call overlay backend: %w", err)
+		return nil, fmt.Errorf("open scaffold staging dir %q: %w", scaffoldDir, err)
 	}
-	defer resp.Body.Close()
-	b, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("overlay backend returned status %d: %s", resp.StatusCode, string(b))
-	}
-	var parsed map[string]interface{}
-	if err := json.Unmarshal(b, &parsed); err != nil {
-		return nil, fmt.Errorf("parse backend overlay response: %w", err)
-	}
-	return parsed, nil
-}
 
-// applyOverlayToScaffold writes the changed files returned by the backend into the scaffoldDir
-// and merges newDependencies into scaffoldDir/package.json as dependency placeholders ("*").
-// It returns a list of written files and any warning errors encountered.
-func applyOverlayToScaffold(scaffoldDir string, changedFiles []map[string]interface{}, newDependencies []string) ([]string, error) {
 	written := []string{}
-	// write changed files (overwrite or create)
+	// write changed files (overwrite or create), consulting the content
+	// cache first so a file whose contents haven't changed since the last
+	// round is neither rewritten nor re-fetched from a cold cache.
 	for _, f := range changedFiles {
 		pathIface, ok := f["path"]
 		if !ok {
@@ -1112,53 +1341,37 @@ func applyOverlayToScaffold(scaffoldDir string, changedFiles []map[string]interf
 			continue
 		}
 		contentStr, _ := contentIface.(string)
-		target := filepath.Join(scaffoldDir, filepath.FromSlash(pathStr))
-
-		if err := os.WriteFile(target, []byte(contentStr), 0o644); err != nil {
-			return written, fmt.Errorf("write file %s: %w", target, err)
+		target, serr := pathguard.SanitizeRelPath(scaffoldDir, pathStr, pathguard.DefaultDenyGlobs)
+		if serr != nil {
+			return written, fmt.Errorf("overlay file %q: %w", pathStr, serr)
 		}
-		written = append(written, pathStr)
-	}
+		hash := filecache.Hash(pathStr, contentStr)
 
-	// merge dependencies into package.json using "*" placeholder
-	pkgPath := filepath.Join(scaffoldDir, "package.json")
-	pkgBytes, err := os.ReadFile(pkgPath)
-	if err != nil {
-		// If package.json missing, still return the written files and warn
-		if len(newDependencies) > 0 {
-			return written, fmt.Errorf("package.json not found in scaffold; cannot merge dependencies")
+		if cache != nil {
+			if existing, err := os.ReadFile(target); err == nil && filecache.Hash(pathStr, string(existing)) == hash {
+				fmt.Printf("unchanged: %s\n", pathStr)
+				continue
+			}
+			if cache.Has(hash) {
+				if err := cache.CopyTo(hash, target); err == nil {
+					written = append(written, pathStr)
+					continue
+				}
+				// fall through to a normal write on cache errors
+			}
 		}
-		return written, nil
-	}
-	var pj map[string]interface{}
-	if err := json.Unmarshal(pkgBytes, &pj); err != nil {
-		return written, fmt.Errorf("invalid package.json: %w", err)
-	}
-	// Ensure dependencies map exists
-	deps, ok := pj["dependencies"].(map[string]interface{})
-	if !ok || deps == nil {
-		deps = map[string]interface{}{}
-	}
-	for _, d := range newDependencies {
-		if d == "" {
-			continue
+
+		if err := d.Write(pathStr, strings.NewReader(contentStr)); err != nil {
+			return written, fmt.Errorf("write file %s: %w", target, err)
 		}
-		if _, exists := deps[d]; !exists {
-			deps[d] = "*" // placeholder; pin locally later with resolver
+		if cache != nil {
+			if err := cache.Put(hash, contentStr); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to cache %s: %v\n", pathStr, err)
+			}
 		}
-	}
-	pj["dependencies"] = deps
-	// write back package.json
-	updated, err := json.MarshalIndent(pj, "", "  ")
-	if err != nil {
-		return written, fmt.Errorf("marshal updated package.json: %w", err)
-	}
-	if err := os.WriteFile(pkgPath, updated, 0o644); err != nil {
-		return written, fmt.Errorf("write package.json: %w", err)
+		written = append(written, pathStr)
 	}
 
-	// NOTE: pinning to exact versions should be done after this step using your dep_resolver
-	// (e.g., call resolve_with_npm_lockfile_fully or run npm install --package-lock-only)
 	return written, nil
 }
 
@@ -1166,3 +1379,49 @@ func exists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
+
+// looksLikeDiskURL reports whether output (the --output flag's value)
+// should be resolved via disk.New instead of used as a plain local path:
+// either the "tar:" shorthand or a real "scheme://" URL (ftp, sftp, s3).
+func looksLikeDiskURL(output string) bool {
+	return strings.HasPrefix(output, "tar:") || strings.Contains(output, "://")
+}
+
+// exportStagingAndCleanup copies everything under the local staging dir
+// into exportDisk and removes the staging dir, for the --output cases that
+// don't point directly at a local path (see runCreateWizard). A nil
+// exportDisk means --output was a plain local path already in its final
+// place, so there's nothing to export.
+func exportStagingAndCleanup(stagingDir string, exportDisk disk.Disk) error {
+	if exportDisk == nil {
+		return nil
+	}
+	defer exportDisk.Close()
+
+	err := filepath.WalkDir(stagingDir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, rerr := filepath.Rel(stagingDir, p)
+		if rerr != nil {
+			return rerr
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			return exportDisk.MkdirAll(rel)
+		}
+		f, ferr := os.Open(p)
+		if ferr != nil {
+			return ferr
+		}
+		defer f.Close()
+		return exportDisk.Write(rel, f)
+	})
+	if err != nil {
+		return fmt.Errorf("export staged scaffold to --output destination: %w", err)
+	}
+	return os.RemoveAll(stagingDir)
+}
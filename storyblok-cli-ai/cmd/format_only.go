@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"storyblok-cli-ai/internal/formatters"
+)
+
+// skipDirs are never descended into by runFormatOnly: dependency trees and
+// VCS metadata are neither generated by this CLI nor safe to rewrite.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+}
+
+// runFormatOnly implements --format-only: it walks dir, running the
+// formatter registry (offline, since a project already on disk has no
+// backend to talk to) against every file with a matching Rule, and rewrites
+// it in place when the formatted output differs. This reuses the same
+// registry --no-format skips during `create`/`replay`/`scaffold replay`, so
+// a project generated with --no-format (or edited by hand afterward) can be
+// formatted later without re-running the wizard.
+func runFormatOnly(ctx context.Context, dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid --format-only path: %w", err)
+	}
+	if !exists(absDir) {
+		return fmt.Errorf("--format-only directory does not exist: %s", absDir)
+	}
+
+	reg, err := formatters.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: formatters config unavailable, using defaults: %v\n", err)
+		reg = formatters.DefaultRegistry()
+	}
+
+	var formatted, unchanged, failed int
+	err = filepath.Walk(absDir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() {
+			if skipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		contentBytes, rerr := os.ReadFile(path)
+		if rerr != nil {
+			return fmt.Errorf("read %s: %w", path, rerr)
+		}
+		relPath, _ := filepath.Rel(absDir, path)
+		out, warn := formatters.Format(ctx, reg, relPath, string(contentBytes), true)
+		if warn != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warn)
+			failed++
+			return nil
+		}
+		if out == string(contentBytes) {
+			unchanged++
+			return nil
+		}
+		if werr := os.WriteFile(path, []byte(out), info.Mode()); werr != nil {
+			return fmt.Errorf("write %s: %w", path, werr)
+		}
+		formatted++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("format-only %s: %w", absDir, err)
+	}
+
+	fmt.Printf("Formatted %d file(s), %d unchanged, %d failed in %s\n", formatted, unchanged, failed, absDir)
+	return nil
+}
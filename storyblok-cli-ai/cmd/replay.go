@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"storyblok-cli-ai/internal/backend"
+	"storyblok-cli-ai/internal/session"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <session-id>",
+	Short: "Re-run a recorded create-wizard session for bug reproduction",
+	Long: "Reads ~/.storyblok-ai-cli/sessions/<session-id>.jsonl (written by `create`) and re-runs the wizard against " +
+		"its recorded answers, so a session file a user shares can be reproduced deterministically. By default the " +
+		"replay still calls a live backend with --backend-url/--backend-transport; pass --dry-run to instead replay " +
+		"the session's own recorded backend responses.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runReplay(cmd, args[0]); err != nil {
+			printStructuredError(cmd, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	replayCmd.Flags().Bool("dry-run", false, "Replay the session's own recorded backend responses instead of calling a live backend")
+	replayCmd.Flags().Int("from-round", 0, "Only replay answers/backend responses recorded at or after this round")
+	replayCmd.Flags().StringP("output", "o", "", "Output folder for the generated project (default: ./<app_name>)")
+	replayCmd.Flags().String("stream-format", "ndjson", `Streaming framing for /generate/stream: "ndjson" or "sse" (use sse behind proxies that buffer chunked NDJSON)`)
+	replayCmd.Flags().Bool("no-cache", false, "Disable the content-addressable overlay file cache (always rewrite files, even if unchanged)")
+	replayCmd.Flags().String("backend-url", defaultBackendURL, `Backend root URL (for --backend-transport=http) or fixtures directory (for --backend-transport=file); ignored with --dry-run`)
+	replayCmd.Flags().String("backend-transport", "http", `Backend transport: "http" or "file"; ignored with --dry-run`)
+	replayCmd.Flags().Bool("offline-deps", false, "Resolve new_dependencies from the bundled version snapshot instead of the npm registry (no network required)")
+	replayCmd.Flags().StringSlice("secret-field", nil, "Field id(s) (in addition to the token) whose recorded answer should be redacted in the new session log this replay writes")
+	replayCmd.Flags().String("event-log", "", "Tee every file_start/file_chunk/file_complete/dependency/warning event received from the backend to this JSONL file, for `scaffold replay` and CI snapshot tests")
+	replayCmd.Flags().String("log-format", "text", `slog handler for the replay's structured log output: "text" (default) or "json"`)
+	replayCmd.Flags().Bool("no-format", false, "Skip the formatter registry entirely, leaving generated files exactly as the backend streamed them")
+	replayCmd.Flags().Int64("max-file-bytes", 0, "Abort the stream if a single file's accumulated size exceeds this many bytes (0 = no limit)")
+	replayCmd.Flags().Int64("max-total-bytes", 0, "Abort the stream if the session's total generated size exceeds this many bytes (0 = no limit)")
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, sessionID string) error {
+	path, err := session.Path(sessionID)
+	if err != nil {
+		return fmt.Errorf("locate session %q: %w", sessionID, err)
+	}
+	events, err := session.Load(path)
+	if err != nil {
+		return fmt.Errorf("load session %q: %w", sessionID, err)
+	}
+
+	fromRound, _ := cmd.Flags().GetInt("from-round")
+	answers := session.Answers(events, fromRound)
+
+	var overrideBk backend.Client
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		overrideBk = newReplayBackendClient(events, fromRound)
+		fmt.Printf("Replaying session %s in dry-run mode (%d recorded answer(s), no live backend calls)...\n", sessionID, len(answers))
+	} else {
+		fmt.Printf("Replaying session %s against a live backend (%d recorded answer(s))...\n", sessionID, len(answers))
+	}
+
+	return runCreateWizard(cmd, answers, overrideBk)
+}
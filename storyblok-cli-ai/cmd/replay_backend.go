@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"storyblok-cli-ai/internal/session"
+)
+
+// replayBackendClient implements backend.Client by handing back a prior
+// session's recorded backend_response events, in the order they were
+// originally received, instead of calling a live backend. Used by
+// `replay --dry-run`. GenerateStream always errors: runStreamingRounds
+// already falls back to Generate() when the streaming endpoint fails, so
+// routing through that existing path replays the same recorded response
+// without a separate stream-fixture format.
+type replayBackendClient struct {
+	responses map[string][]map[string]interface{}
+	next      map[string]int
+}
+
+func newReplayBackendClient(events []session.Event, fromRound int) *replayBackendClient {
+	return &replayBackendClient{
+		responses: session.BackendResponses(events, fromRound),
+		next:      map[string]int{},
+	}
+}
+
+func (c *replayBackendClient) pop(method string) (map[string]interface{}, error) {
+	list := c.responses[method]
+	i := c.next[method]
+	if i >= len(list) {
+		return nil, fmt.Errorf("no recorded %s response left to replay", method)
+	}
+	c.next[method] = i + 1
+	return list[i], nil
+}
+
+func (c *replayBackendClient) Generate(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.pop("generate")
+}
+
+func (c *replayBackendClient) Questions(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.pop("questions")
+}
+
+func (c *replayBackendClient) Overlay(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.pop("overlay")
+}
+
+func (c *replayBackendClient) GenerateStream(payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("replay does not record raw stream events; falls back to the recorded generate response")
+}
@@ -21,9 +21,12 @@ func Execute() error {
 func init() {
 	// Add subcommands here
 	rootCmd.AddCommand(createAppCmd)
+	rootCmd.AddCommand(cacheCmd)
 
 	// Optional: global persistent flags
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().BoolP("assume-yes", "y", false, "Assume \"yes\" to prompts (e.g. installing the Storyblok CLI); for CI/non-interactive use")
+	rootCmd.PersistentFlags().Bool("yes", false, "Alias for --assume-yes")
 }
 
 // Utility for structured fatal errors at the root level
@@ -31,3 +34,12 @@ func fatal(err error) {
 	fmt.Fprintln(os.Stderr, err)
 	os.Exit(1)
 }
+
+// assumeYes reports whether the user passed --yes or --assume-yes, so
+// prompts (e.g. installing the Storyblok CLI) can be skipped for CI and
+// other non-interactive invocations.
+func assumeYes(cmd *cobra.Command) bool {
+	yes, _ := cmd.Flags().GetBool("yes")
+	assumeYes, _ := cmd.Flags().GetBool("assume-yes")
+	return yes || assumeYes
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"storyblok-cli-ai/internal/deps"
+	"storyblok-cli-ai/internal/eventlog"
+	"storyblok-cli-ai/internal/formatters"
+	"storyblok-cli-ai/internal/scaffold/disk"
+)
+
+// scaffoldCmd groups low-level scaffold utilities that operate on local
+// state (a recorded --event-log, a directory) instead of a live backend.
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold",
+	Short: "Low-level scaffold utilities that don't require a live backend",
+}
+
+var scaffoldReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Reconstruct a project from a recorded --event-log, without contacting the backend",
+	Long: "Re-runs the exact same file_start/file_chunk/file_complete/dependency/warning state machine `create` drives " +
+		"live, but reads it from a JSONL --event-log recorded by a prior `create`/`replay` run instead of a live " +
+		"/generate/stream connection. This reproduces the scaffold's files byte-for-byte with no backend, network, or " +
+		"wizard prompts involved, which is what makes it useful for debugging a shared event log and for CI " +
+		"snapshot-testing the CLI against recorded backend streams.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runScaffoldReplay(cmd); err != nil {
+			printStructuredError(cmd, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	scaffoldReplayCmd.Flags().String("event-log", "", "JSONL event log recorded by a prior run's --event-log flag (required)")
+	scaffoldReplayCmd.Flags().String("out", "", "Directory to reconstruct the project into; must not already exist (required)")
+	scaffoldReplayCmd.Flags().String("log-format", "text", `slog handler for the replay's own log output: "text" (default) or "json"`)
+	scaffoldReplayCmd.Flags().Bool("no-format", false, "Reconstruct files exactly as recorded, skipping the formatter registry")
+	scaffoldReplayCmd.Flags().Int64("max-file-bytes", 0, "Abort replay if a recorded file's accumulated size exceeds this many bytes (0 = no limit)")
+	scaffoldReplayCmd.Flags().Int64("max-total-bytes", 0, "Abort replay if the reconstructed project's total size exceeds this many bytes (0 = no limit)")
+	_ = scaffoldReplayCmd.MarkFlagRequired("event-log")
+	_ = scaffoldReplayCmd.MarkFlagRequired("out")
+	scaffoldCmd.AddCommand(scaffoldReplayCmd)
+	rootCmd.AddCommand(scaffoldCmd)
+}
+
+func runScaffoldReplay(cmd *cobra.Command) error {
+	eventLogPath, _ := cmd.Flags().GetString("event-log")
+	outDir, _ := cmd.Flags().GetString("out")
+	logFormat, _ := cmd.Flags().GetString("log-format")
+	logger := eventlog.NewLogger(logFormat)
+	ctx := eventlog.WithLogger(cmd.Context(), logger)
+
+	absOut, err := filepath.Abs(outDir)
+	if err != nil {
+		return fmt.Errorf("invalid --out path: %w", err)
+	}
+	if exists(absOut) {
+		return fmt.Errorf("--out directory already exists: %s (remove or choose another name)", absOut)
+	}
+	if err := os.MkdirAll(absOut, 0o755); err != nil {
+		return fmt.Errorf("create --out directory: %w", err)
+	}
+
+	entries, err := eventlog.Load(eventLogPath)
+	if err != nil {
+		return fmt.Errorf("load event log %q: %w", eventLogPath, err)
+	}
+
+	stagingDisk, err := disk.New(absOut)
+	if err != nil {
+		return fmt.Errorf("open --out dir %q: %w", absOut, err)
+	}
+	tmpDir, err := os.MkdirTemp("", "ai_scaffold_replay_*")
+	if err != nil {
+		return fmt.Errorf("create replay temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var reg *formatters.Registry
+	if noFormat, _ := cmd.Flags().GetBool("no-format"); !noFormat {
+		reg, err = formatters.Load()
+		if err != nil {
+			logger.Warn("formatters config unavailable, using defaults", "error", err)
+			reg = formatters.DefaultRegistry()
+		}
+	}
+
+	maxFileBytes, _ := cmd.Flags().GetInt64("max-file-bytes")
+	maxTotalBytes, _ := cmd.Flags().GetInt64("max-total-bytes")
+
+	var depSuggestions []deps.Suggestion
+	state := newStreamEventState(ctx, tmpDir, stagingDisk, nil, &depSuggestions, reg, false, maxFileBytes, maxTotalBytes)
+
+	var lastCheckpoint string
+	for _, entry := range entries {
+		switch entry.Event {
+		case "file_start":
+			var m struct {
+				Path string `json:"path"`
+			}
+			if err := json.Unmarshal(entry.Payload, &m); err != nil {
+				continue
+			}
+			if serr := state.fileStart(m.Path); serr != nil {
+				return serr
+			}
+		case "file_chunk":
+			var m struct {
+				Path  string `json:"path"`
+				Chunk string `json:"chunk"`
+			}
+			if err := json.Unmarshal(entry.Payload, &m); err != nil {
+				continue
+			}
+			if serr := state.fileChunk(m.Path, m.Chunk); serr != nil {
+				return serr
+			}
+		case "file_complete":
+			var m struct {
+				Path string `json:"path"`
+			}
+			if err := json.Unmarshal(entry.Payload, &m); err != nil {
+				continue
+			}
+			_, checkpoint, ok, ferr := state.fileComplete(m.Path, entry.EventID, lastCheckpoint)
+			if ferr != nil {
+				return fmt.Errorf("replay file_complete %q: %w", m.Path, ferr)
+			}
+			if !ok {
+				logger.Warn("file_complete with no matching file_start, skipping", "path", m.Path)
+				continue
+			}
+			lastCheckpoint = checkpoint
+		case "dependency":
+			var m struct {
+				Name       string        `json:"name"`
+				Version    string        `json:"version"`
+				Confidence float64       `json:"confidence"`
+				Candidates []interface{} `json:"candidates"`
+			}
+			if err := json.Unmarshal(entry.Payload, &m); err != nil {
+				continue
+			}
+			state.dependency(m.Name, m.Version, m.Confidence, m.Candidates)
+		case "warning":
+			var payload interface{}
+			_ = json.Unmarshal(entry.Payload, &payload)
+			state.warning(payload)
+		default:
+			logger.Debug("skipping non-scaffold event", "event", entry.Event)
+		}
+	}
+	state.finish()
+
+	logger.Info("replay complete", "files", len(state.completedFiles), "out", absOut)
+	fmt.Println("Reconstructed project at:", absOut)
+	return nil
+}
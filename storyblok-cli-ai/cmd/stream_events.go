@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/schollz/progressbar/v3"
+
+	"storyblok-cli-ai/internal/deps"
+	"storyblok-cli-ai/internal/eventlog"
+	"storyblok-cli-ai/internal/formatters"
+	"storyblok-cli-ai/internal/pathguard"
+	"storyblok-cli-ai/internal/scaffold"
+	"storyblok-cli-ai/internal/scaffold/disk"
+)
+
+// streamEventState is the file_start/file_chunk/file_complete/dependency/
+// warning state machine driven by the switch in runStreamingRounds. It's
+// factored out here so `scaffold replay` can drive the exact same state
+// machine from a recorded --event-log instead of a live /generate/stream
+// connection, and reproduce the scaffold byte-for-byte without a backend.
+type streamEventState struct {
+	ctx            context.Context
+	tmpDir         string
+	tempFiles      map[string]string
+	stagingDisk    disk.Disk
+	jf             *os.File // tmpDir-local journal; nil disables it (see stream_journal.go)
+	completedFiles []scaffold.FileOut
+	generatedCount int
+	depSuggestions *[]deps.Suggestion
+	pb             *progressbar.ProgressBar
+	formatters     *formatters.Registry // nil disables formatting (--no-format)
+	offlineFormat  bool                 // skip AllowNetwork formatter candidates when true
+	fileBytes      map[string]int64     // running per-file byte count, reset on file_start
+	totalBytes     int64                // running per-session byte count across all files
+	maxFileBytes   int64                // 0 disables the per-file quota (--max-file-bytes)
+	maxTotalBytes  int64                // 0 disables the per-session quota (--max-total-bytes)
+}
+
+func newStreamEventState(ctx context.Context, tmpDir string, stagingDisk disk.Disk, jf *os.File, depSuggestions *[]deps.Suggestion, reg *formatters.Registry, offlineFormat bool, maxFileBytes, maxTotalBytes int64) *streamEventState {
+	return &streamEventState{
+		ctx:            ctx,
+		tmpDir:         tmpDir,
+		tempFiles:      map[string]string{},
+		stagingDisk:    stagingDisk,
+		jf:             jf,
+		depSuggestions: depSuggestions,
+		formatters:     reg,
+		offlineFormat:  offlineFormat,
+		fileBytes:      map[string]int64{},
+		maxFileBytes:   maxFileBytes,
+		maxTotalBytes:  maxTotalBytes,
+	}
+}
+
+func (s *streamEventState) logger() *slog.Logger {
+	return eventlog.FromContext(s.ctx)
+}
+
+// fileStart opens path's per-file temp file, truncating it if a prior
+// run already left one (fresh file_start always means a fresh file). It
+// rejects a path the backend has no business sending -- absolute, escaping
+// tmpDir via "..", or targeting .git/node_modules -- before touching disk.
+func (s *streamEventState) fileStart(path string) error {
+	if _, err := pathguard.SanitizeRelPath(s.tmpDir, path, pathguard.DefaultDenyGlobs); err != nil {
+		return fmt.Errorf("file_start: %w", err)
+	}
+	tf := journalTempFilePath(s.tmpDir, path)
+	_ = os.MkdirAll(filepath.Dir(tf), 0o755)
+	_ = os.WriteFile(tf, []byte(""), 0o644)
+	s.tempFiles[path] = tf
+	s.fileBytes[path] = 0
+	appendJournal(s.jf, journalEvent{Type: journalFileStart, Path: path})
+	s.logger().Debug("file_start", "path", path)
+	return nil
+}
+
+// fileChunk appends chunk to path's temp file, implicitly starting the
+// file if no file_start was seen for it (mirrors the historical behavior
+// of the inline switch this replaced). It aborts with an error once path's
+// accumulated size passes maxFileBytes, or the session's total does
+// maxTotalBytes, so a runaway or malicious stream can't fill the disk.
+func (s *streamEventState) fileChunk(path, chunk string) error {
+	tf, ok := s.tempFiles[path]
+	if !ok {
+		if err := s.fileStart(path); err != nil {
+			return err
+		}
+		tf = s.tempFiles[path]
+	}
+
+	n := int64(len(chunk))
+	if s.maxFileBytes > 0 && s.fileBytes[path]+n > s.maxFileBytes {
+		return fmt.Errorf("file_chunk: %q exceeds --max-file-bytes (%d)", path, s.maxFileBytes)
+	}
+	if s.maxTotalBytes > 0 && s.totalBytes+n > s.maxTotalBytes {
+		return fmt.Errorf("file_chunk: session exceeds --max-total-bytes (%d)", s.maxTotalBytes)
+	}
+
+	f, ferr := os.OpenFile(tf, os.O_APPEND|os.O_WRONLY, 0o644)
+	if ferr == nil {
+		_, _ = f.WriteString(chunk)
+		f.Close()
+	}
+	s.fileBytes[path] += n
+	s.totalBytes += n
+	if fi, serr := os.Stat(tf); serr == nil {
+		appendJournal(s.jf, journalEvent{Type: journalFileChunk, Path: path, Offset: fi.Size()})
+	}
+	s.logger().Debug("file_chunk", "path", path, "bytes", len(chunk))
+	return nil
+}
+
+// fileComplete formats path's accumulated content, writes it to
+// stagingDisk, and returns the final content plus the checkpoint ID the
+// caller should remember (eventID if the backend sent one over SSE,
+// otherwise a content hash). It reports (ok=false) rather than an error
+// for a file_complete with no matching file_start, since a malformed or
+// out-of-order event shouldn't abort an otherwise-healthy stream.
+func (s *streamEventState) fileComplete(path, eventID string, priorCheckpoint string) (content, checkpoint string, ok bool, err error) {
+	tf, exists := s.tempFiles[path]
+	if !exists {
+		return "", "", false, nil
+	}
+
+	contentBytes, _ := os.ReadFile(tf)
+	content = string(contentBytes)
+
+	if s.formatters != nil {
+		formatted, warn := formatters.Format(s.ctx, s.formatters, path, content, s.offlineFormat)
+		content = formatted
+		if warn != "" {
+			s.warning(warn)
+		}
+	}
+
+	if werr := s.stagingDisk.Write(path, strings.NewReader(content)); werr != nil {
+		return "", "", true, fmt.Errorf("write generated file %s: %w", path, werr)
+	}
+
+	s.completedFiles = append(s.completedFiles, scaffold.FileOut{Path: path, Content: content})
+	s.generatedCount++
+	s.logger().Info("file_complete", "path", path, "bytes", len(content))
+
+	checkpoint = eventID
+	if checkpoint == "" {
+		checkpoint = fileCheckpointID(s.generatedCount, path, content)
+	}
+	appendJournal(s.jf, journalEvent{Type: journalFileComplete, Path: path, Hash: journalHash(path, content), EventID: checkpoint})
+
+	if s.pb == nil {
+		s.pb = progressbar.NewOptions(-1,
+			progressbar.OptionSetDescription("Generating files"),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSpinnerType(14),
+		)
+	}
+	_ = s.pb.Add(1)
+
+	return content, checkpoint, true, nil
+}
+
+// dependency records a dependency-resolution event from the backend.
+func (s *streamEventState) dependency(name, version string, confidence float64, candidates []interface{}) {
+	appendJournal(s.jf, journalEvent{Type: journalDependency, Name: name, Version: version})
+	if version != "" && s.depSuggestions != nil {
+		*s.depSuggestions = append(*s.depSuggestions, deps.Suggestion{Name: name, Version: version, Confidence: confidence})
+	}
+	switch {
+	case version != "":
+		s.logger().Info("dependency resolved", "name", name, "version", version, "confidence", confidence)
+	case len(candidates) > 0:
+		s.logger().Warn("dependency not found", "name", name, "candidates", candidates)
+	default:
+		s.logger().Warn("dependency not found", "name", name)
+	}
+}
+
+// warning logs a backend-emitted warning event, whether it's a plain
+// string or a structured payload.
+func (s *streamEventState) warning(payload interface{}) {
+	if str, ok := payload.(string); ok {
+		s.logger().Warn(str)
+		return
+	}
+	bs, _ := json.Marshal(payload)
+	s.logger().Warn("backend warning", "payload", string(bs))
+}
+
+// finish marks the progress bar (if one was started) complete.
+func (s *streamEventState) finish() {
+	if s.pb != nil {
+		_ = s.pb.Finish()
+	}
+}
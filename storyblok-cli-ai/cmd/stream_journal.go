@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"storyblok-cli-ai/internal/scaffold"
+)
+
+// journalFileName is the append-only event journal written under a
+// streaming run's own tmpDir, alongside the files it's writing. Unlike the
+// streamCheckpoint under ~/.storyblok-ai-cli/streams/<session>.json, it
+// travels with tmpDir, so --resume-dir can rebuild a session from that
+// directory alone (e.g. after copying it to another machine, or when the
+// central checkpoint dir is unavailable).
+const journalFileName = ".scaffold-journal.jsonl"
+
+type journalEventType string
+
+const (
+	journalSessionMeta  journalEventType = "session_meta"
+	journalFileStart    journalEventType = "file_start"
+	journalFileChunk    journalEventType = "file_chunk"
+	journalFileComplete journalEventType = "file_complete"
+	journalDependency   journalEventType = "dependency"
+	journalFollowup     journalEventType = "followup_answer"
+)
+
+// journalEvent is one line of the journal. Fields are omitted when unused
+// by Type, so each line stays close to the size of the event it records.
+type journalEvent struct {
+	Type      journalEventType `json:"type"`
+	Path      string           `json:"path,omitempty"`
+	Offset    int64            `json:"offset,omitempty"`
+	Hash      string           `json:"hash,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	Version   string           `json:"version,omitempty"`
+	ID        string           `json:"id,omitempty"`
+	Value     string           `json:"value,omitempty"`
+	SessionID string           `json:"session_id,omitempty"`
+	AbsTarget string           `json:"abs_target,omitempty"`
+	Payload   json.RawMessage  `json:"payload,omitempty"`
+	EventID   string           `json:"event_id,omitempty"`
+}
+
+// openJournal opens tmpDir's journal file for appending, creating it if this
+// is the first event for the run. A nil return (with a logged warning at
+// the call site) just disables journaling for the run; it's a recovery aid,
+// not required for the stream to proceed.
+func openJournal(tmpDir string) (*os.File, error) {
+	return os.OpenFile(filepath.Join(tmpDir, journalFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
+
+// appendJournal writes ev as one JSON line to jf. jf may be nil (journaling
+// disabled), in which case it's a no-op.
+func appendJournal(jf *os.File, ev journalEvent) {
+	if jf == nil {
+		return
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	_, _ = jf.Write(b)
+}
+
+// journalTempFilePath mirrors the tempFiles naming scheme used by the
+// stream reader (path -> tmpDir/path-with-slashes-replaced), so the journal
+// and the reader always agree on where a given path's bytes live on disk.
+func journalTempFilePath(tmpDir, path string) string {
+	return filepath.Join(tmpDir, strings.ReplaceAll(path, "/", "__"))
+}
+
+// journalHash returns the checkpoint hash recorded alongside a
+// journalFileComplete event, matching fileCheckpointID's path+content
+// scheme so the two resume mechanisms stay consistent.
+func journalHash(path, content string) string {
+	h := md5.Sum([]byte(path + "\x00" + content))
+	return hex.EncodeToString(h[:])
+}
+
+// journalState is what --resume-dir rebuilds from a tmpDir's journal: the
+// payload and session metadata from the last session_meta event (overlaid
+// with any followup_answer events recorded since), the files already
+// completed, and the paths of files still in progress (their byte offsets
+// come from journalResumeOffsets).
+type journalState struct {
+	SessionID      string
+	AbsTarget      string
+	Payload        map[string]interface{}
+	CompletedFiles []scaffold.FileOut
+	LastCheckpoint string
+	InProgress     []string // paths with a file_start but no file_complete
+}
+
+// loadJournal reads every event from tmpDir's journal, in order.
+func loadJournal(tmpDir string) ([]journalEvent, error) {
+	f, err := os.Open(filepath.Join(tmpDir, journalFileName))
+	if err != nil {
+		return nil, fmt.Errorf("open stream journal: %w", err)
+	}
+	defer f.Close()
+
+	var events []journalEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var ev journalEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return nil, fmt.Errorf("parse stream journal: %w", err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read stream journal: %w", err)
+	}
+	return events, nil
+}
+
+// rebuildJournalState replays events into a journalState. completedFiles'
+// content is read back from tmpDir's per-path temp files, so a --resume-dir
+// run needs nothing besides the directory the journal lives in.
+func rebuildJournalState(tmpDir string, events []journalEvent) (*journalState, error) {
+	st := &journalState{}
+	inProgress := map[string]bool{}
+	completed := map[string]bool{}
+	followups := map[string]string{}
+
+	for _, ev := range events {
+		switch ev.Type {
+		case journalSessionMeta:
+			st.SessionID = ev.SessionID
+			st.AbsTarget = ev.AbsTarget
+			var payload map[string]interface{}
+			if err := json.Unmarshal(ev.Payload, &payload); err == nil {
+				st.Payload = payload
+			}
+		case journalFileStart:
+			inProgress[ev.Path] = true
+		case journalFileComplete:
+			delete(inProgress, ev.Path)
+			completed[ev.Path] = true
+			if ev.EventID != "" {
+				st.LastCheckpoint = ev.EventID
+			}
+		case journalFollowup:
+			followups[ev.ID] = ev.Value
+		}
+	}
+
+	if st.Payload == nil {
+		return nil, fmt.Errorf("stream journal has no session_meta event to resume from")
+	}
+	if len(followups) > 0 {
+		userAns, _ := st.Payload["user_answers"].(map[string]interface{})
+		if userAns == nil {
+			userAns = map[string]interface{}{}
+		}
+		existing := map[string]interface{}{}
+		if fa, ok := userAns["followup_answers"].(map[string]interface{}); ok {
+			existing = fa
+		}
+		for k, v := range followups {
+			existing[k] = v
+		}
+		userAns["followup_answers"] = existing
+		st.Payload["user_answers"] = userAns
+	}
+
+	for path := range completed {
+		b, err := os.ReadFile(journalTempFilePath(tmpDir, path))
+		if err != nil {
+			// Temp file is gone; drop it from completedFiles so the backend
+			// re-sends it rather than resuming with a hole.
+			continue
+		}
+		st.CompletedFiles = append(st.CompletedFiles, scaffold.FileOut{Path: path, Content: string(b)})
+	}
+	for path := range inProgress {
+		st.InProgress = append(st.InProgress, path)
+	}
+	return st, nil
+}
+
+// journalResumeOffsets stats each in-progress path's temp file in tmpDir and
+// returns path -> current byte length. This is the truncation guard: a file
+// that was mid-chunk when the process died may have more (or fewer, if the
+// last write was torn) bytes on disk than the last recorded file_chunk
+// offset, so we trust the file itself over the journal.
+func journalResumeOffsets(tmpDir string, inProgress []string) map[string]int64 {
+	offsets := map[string]int64{}
+	for _, path := range inProgress {
+		fi, err := os.Stat(journalTempFilePath(tmpDir, path))
+		if err != nil {
+			continue
+		}
+		offsets[path] = fi.Size()
+	}
+	return offsets
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"storyblok-cli-ai/internal/backend"
+	"storyblok-cli-ai/internal/scaffold"
+)
+
+// Tuning for reconnecting a dropped /generate/stream connection: start at
+// streamBackoffBase, double on each attempt up to streamBackoffCap, with
+// jitter so many clients reconnecting at once don't thunder-herd the
+// backend. maxStreamReconnects bounds how many times we'll retry before
+// giving up and telling the user to --resume later.
+const (
+	streamBackoffBase   = 500 * time.Millisecond
+	streamBackoffCap    = 30 * time.Second
+	maxStreamReconnects = 10
+)
+
+// streamCheckpoint is the on-disk record of a resumable create-wizard
+// streaming session, written to ~/.storyblok-ai-cli/streams/<session>.json
+// after every file_complete event so --resume can pick back up with the
+// same tmpDir and completedFiles instead of replaying the whole wizard.
+type streamCheckpoint struct {
+	SessionID      string                 `json:"session_id"`
+	AbsTarget      string                 `json:"abs_target"`
+	TmpDir         string                 `json:"tmp_dir"`
+	Payload        map[string]interface{} `json:"payload"`
+	CompletedFiles []scaffold.FileOut     `json:"completed_files"`
+	LastCheckpoint string                 `json:"last_checkpoint"`
+	UpdatedAt      string                 `json:"updated_at"`
+}
+
+func streamSessionDir() string {
+	home, _ := os.UserHomeDir()
+	dir := filepath.Join(home, ".storyblok-ai-cli", "streams")
+	_ = os.MkdirAll(dir, 0o755)
+	return dir
+}
+
+func streamSessionPath(sessionID string) string {
+	return filepath.Join(streamSessionDir(), sessionID+".json")
+}
+
+// newSessionID returns a short random hex ID identifying a fresh streaming
+// session, used both as the checkpoint filename and as the value users
+// pass to --resume.
+func newSessionID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func loadStreamCheckpoint(sessionID string) (*streamCheckpoint, error) {
+	b, err := os.ReadFile(streamSessionPath(sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	var cp streamCheckpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("parse checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+func saveStreamCheckpoint(cp *streamCheckpoint) error {
+	cp.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(streamSessionPath(cp.SessionID), b, 0o600)
+}
+
+// fileCheckpointID returns a checkpoint ID for a completed file: a
+// monotonically increasing sequence plus an md5 of its path+content, used
+// as X-Resume-From when the backend itself doesn't send an event ID.
+func fileCheckpointID(seq int, path, content string) string {
+	h := md5.Sum([]byte(path + "\x00" + content))
+	return fmt.Sprintf("%d:%s", seq, hex.EncodeToString(h[:]))
+}
+
+// streamBackoffDelay returns the delay before reconnect attempt (1-indexed):
+// streamBackoffBase doubled per attempt, capped at streamBackoffCap, ±20%
+// jitter.
+func streamBackoffDelay(attempt int) time.Duration {
+	d := streamBackoffBase
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= streamBackoffCap {
+			d = streamBackoffCap
+			break
+		}
+	}
+	jitter := 1 + (rand.Float64()*0.4 - 0.2) // [0.8, 1.2]
+	return time.Duration(float64(d) * jitter)
+}
+
+// connectStreamWithRetry calls bk.GenerateStream, retrying with
+// streamBackoffDelay on transport-level failures (not on a non-2xx
+// response, which is almost certainly not transient) up to
+// maxStreamReconnects times before giving up. This is a reconnect loop for
+// an already-dropped stream (using resumeFrom so the backend can skip
+// files we already have); it's on top of, not a replacement for, the
+// request-level retry the backend package's resilient HTTP transport
+// already does per attempt.
+func connectStreamWithRetry(bk backend.Client, payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxStreamReconnects; attempt++ {
+		if attempt > 0 {
+			delay := streamBackoffDelay(attempt)
+			fmt.Fprintf(os.Stderr, "stream connection lost (%v); reconnecting in %s (attempt %d/%d)...\n", lastErr, delay.Round(time.Millisecond), attempt, maxStreamReconnects)
+			time.Sleep(delay)
+		}
+		rc, err := bk.GenerateStream(payload, streamFormat, resumeFrom)
+		if err == nil {
+			return rc, nil
+		}
+		lastErr = err
+		var statusErr *backend.StatusError
+		if errors.As(err, &statusErr) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("stream connection failed after %d attempts: %w", maxStreamReconnects+1, lastErr)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"storyblok-cli-ai/internal/session"
+)
+
+// wizardIO abstracts the create wizard's user prompts so the replay
+// subcommand can feed back a prior session's recorded answers instead of
+// reading the terminal. Every implementation also records answer events
+// (the terminal one records prompt events too) through a session.Recorder,
+// so the session log stays a replayable transcript regardless of where the
+// answers came from.
+type wizardIO interface {
+	AskInput(round int, id, message, deflt string) (string, error)
+	AskSelect(round int, id, message string, options []string, deflt string) (string, error)
+}
+
+// liveWizardIO prompts interactively via survey.
+type liveWizardIO struct {
+	rec *session.Recorder
+}
+
+func (w *liveWizardIO) AskInput(round int, id, message, deflt string) (string, error) {
+	w.rec.Record(session.EventPrompt, round, map[string]interface{}{"id": id, "message": message, "default": deflt})
+	var resp string
+	if err := survey.AskOne(&survey.Input{Message: message, Default: deflt}, &resp); err != nil {
+		return "", err
+	}
+	w.rec.Record(session.EventAnswer, round, map[string]interface{}{"id": id, "value": w.rec.RedactIfSecret(id, resp)})
+	return resp, nil
+}
+
+func (w *liveWizardIO) AskSelect(round int, id, message string, options []string, deflt string) (string, error) {
+	w.rec.Record(session.EventPrompt, round, map[string]interface{}{"id": id, "message": message, "options": options, "default": deflt})
+	var resp string
+	if err := survey.AskOne(&survey.Select{Message: message, Options: options, Default: deflt}, &resp); err != nil {
+		return "", err
+	}
+	w.rec.Record(session.EventAnswer, round, map[string]interface{}{"id": id, "value": resp})
+	return resp, nil
+}
+
+// replayWizardIO answers every prompt from a prior session's recorded
+// answers (keyed by id) instead of the terminal, so `replay` can re-run
+// the wizard deterministically. A missing id falls back to deflt.
+type replayWizardIO struct {
+	answers map[string]string
+	rec     *session.Recorder
+}
+
+func (w *replayWizardIO) AskInput(round int, id, message, deflt string) (string, error) {
+	v, ok := w.answers[id]
+	if !ok {
+		v = deflt
+	}
+	fmt.Printf("replay: %s -> %q\n", message, w.rec.RedactIfSecret(id, v))
+	w.rec.Record(session.EventAnswer, round, map[string]interface{}{"id": id, "value": w.rec.RedactIfSecret(id, v)})
+	return v, nil
+}
+
+func (w *replayWizardIO) AskSelect(round int, id, message string, options []string, deflt string) (string, error) {
+	return w.AskInput(round, id, message, deflt)
+}
@@ -0,0 +1,65 @@
+// Package backend abstracts the AI generation backend the create wizard
+// talks to, so cmd no longer inlines http.Client/JSON-marshaling/retry
+// logic for every endpoint. New returns an HTTP client (resilient by
+// default) or a file:// client that replays recorded fixtures for offline
+// development and tests.
+package backend
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Client is everything the create wizard needs from a backend: the three
+// plain JSON endpoints plus the streaming one.
+type Client interface {
+	// Generate posts payload to the non-streaming /generate/ endpoint.
+	Generate(payload map[string]interface{}) (map[string]interface{}, error)
+	// GenerateStream posts payload to /generate/stream and returns the
+	// response body for the caller to read NDJSON/SSE events from.
+	// streamFormat selects the framing ("ndjson" or "sse"); resumeFrom,
+	// if non-empty, asks the backend to skip files already flushed
+	// before a previous connection dropped.
+	GenerateStream(payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error)
+	// Overlay posts the base scaffold to /generate/overlay.
+	Overlay(payload map[string]interface{}) (map[string]interface{}, error)
+	// Questions posts to /generate/questions for a round of follow-up
+	// questions.
+	Questions(payload map[string]interface{}) (map[string]interface{}, error)
+}
+
+// recordEnvVar, when set to any non-empty value, wraps the returned
+// Client so every call is teed to ~/.storyblok-ai-cli/recordings/<ts>/.
+const recordEnvVar = "STORYBLOK_CLI_RECORD"
+
+// New returns a Client for backendURL using the named transport:
+//   - "http" (the default): real HTTP calls, wrapped with retry + a
+//     circuit breaker that falls back to the last-known-good response.
+//   - "file": reads pre-recorded JSON/NDJSON fixtures from the directory
+//     named by backendURL (a bare path or a file:// URL), for offline
+//     development and tests.
+func New(backendURL, transport string) (Client, error) {
+	var c Client
+	switch transport {
+	case "file":
+		fc, err := newFileClient(backendURL)
+		if err != nil {
+			return nil, err
+		}
+		c = fc
+	case "", "http":
+		c = newResilientClient(newHTTPClient(backendURL))
+	default:
+		return nil, fmt.Errorf("unknown backend transport %q (want \"http\" or \"file\")", transport)
+	}
+
+	if os.Getenv(recordEnvVar) != "" {
+		rc, err := newRecordingClient(c)
+		if err != nil {
+			return nil, fmt.Errorf("set up backend recording: %w", err)
+		}
+		c = rc
+	}
+	return c, nil
+}
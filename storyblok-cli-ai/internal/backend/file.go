@@ -0,0 +1,72 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileClient replays pre-recorded JSON/NDJSON fixtures from a directory
+// instead of calling a live backend, so the wizard can be exercised
+// offline (development, tests, recorded-session replay) without a FastAPI
+// backend running.
+//
+// Fixture layout, all relative to dir:
+//
+//	generate.json    - Generate() response
+//	questions.json   - Questions() response
+//	overlay.json     - Overlay() response
+//	stream.ndjson    - GenerateStream() body, one event per line
+type fileClient struct {
+	dir string
+}
+
+func newFileClient(rawURL string) (*fileClient, error) {
+	dir := strings.TrimPrefix(rawURL, "file://")
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("file transport requires a fixtures directory, e.g. --backend-url file:///path/to/fixtures")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open fixtures dir %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixtures path %s is not a directory", dir)
+	}
+	return &fileClient{dir: dir}, nil
+}
+
+func (c *fileClient) Generate(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.readJSON("generate.json")
+}
+
+func (c *fileClient) Questions(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.readJSON("questions.json")
+}
+
+func (c *fileClient) Overlay(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.readJSON("overlay.json")
+}
+
+func (c *fileClient) GenerateStream(payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(c.dir, "stream.ndjson"))
+	if err != nil {
+		return nil, fmt.Errorf("read fixture stream.ndjson: %w", err)
+	}
+	return f, nil
+}
+
+func (c *fileClient) readJSON(name string) (map[string]interface{}, error) {
+	b, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("read fixture %s: %w", name, err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return nil, fmt.Errorf("parse fixture %s: %w", name, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,111 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StatusError reports a non-2xx HTTP response from the backend. Callers
+// (and the resilient client's retry logic) can type-assert it to inspect
+// Code.
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("backend returned status %d: %s", e.Code, e.Body)
+}
+
+// httpClient is the real, over-the-wire transport. base is the backend's
+// root (e.g. http://127.0.0.1:8000); the /generate/... paths are appended
+// per call.
+type httpClient struct {
+	base   string
+	client *http.Client
+}
+
+func newHTTPClient(backendURL string) *httpClient {
+	return &httpClient{
+		base:   strings.TrimSuffix(backendURL, "/"),
+		client: &http.Client{Timeout: 180 * time.Second},
+	}
+}
+
+func (c *httpClient) Generate(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.postJSON(c.base+"/generate/", payload)
+}
+
+func (c *httpClient) Questions(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.postJSON(c.base+"/generate/questions", payload)
+}
+
+func (c *httpClient) Overlay(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.postJSON(c.base+"/generate/overlay", payload)
+}
+
+func (c *httpClient) postJSON(url string, payload map[string]interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{Code: resp.StatusCode, Body: string(b)}
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return nil, fmt.Errorf("parse backend response: %w", err)
+	}
+	return parsed, nil
+}
+
+func (c *httpClient) GenerateStream(payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequest("POST", c.base+"/generate/stream", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if streamFormat == "sse" {
+		req.Header.Set("Accept", "text/event-stream")
+	} else {
+		req.Header.Set("Accept", "application/x-ndjson")
+	}
+	if resumeFrom != "" {
+		req.Header.Set("X-Resume-From", resumeFrom)
+	}
+
+	// No timeout on the streaming client: these connections are
+	// intentionally long-lived.
+	streamClient := &http.Client{}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &StatusError{Code: resp.StatusCode, Body: string(b)}
+	}
+	return resp.Body, nil
+}
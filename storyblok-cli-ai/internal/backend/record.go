@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// recordingClient wraps another Client and tees every request/response
+// to ~/.storyblok-ai-cli/recordings/<timestamp>/, numbered in call order,
+// for later offline replay via the file transport or debugging a flaky
+// backend.
+type recordingClient struct {
+	inner Client
+	dir   string
+
+	mu  sync.Mutex
+	seq int
+}
+
+func newRecordingClient(inner Client) (*recordingClient, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("determine home dir: %w", err)
+	}
+	dir := filepath.Join(home, ".storyblok-ai-cli", "recordings", time.Now().UTC().Format("20060102T150405Z"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create recordings dir: %w", err)
+	}
+	return &recordingClient{inner: inner, dir: dir}, nil
+}
+
+func (c *recordingClient) Generate(payload map[string]interface{}) (map[string]interface{}, error) {
+	resp, err := c.inner.Generate(payload)
+	c.recordJSON("generate", payload, resp, err)
+	return resp, err
+}
+
+func (c *recordingClient) Questions(payload map[string]interface{}) (map[string]interface{}, error) {
+	resp, err := c.inner.Questions(payload)
+	c.recordJSON("questions", payload, resp, err)
+	return resp, err
+}
+
+func (c *recordingClient) Overlay(payload map[string]interface{}) (map[string]interface{}, error) {
+	resp, err := c.inner.Overlay(payload)
+	c.recordJSON("overlay", payload, resp, err)
+	return resp, err
+}
+
+func (c *recordingClient) GenerateStream(payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error) {
+	rc, err := c.inner.GenerateStream(payload, streamFormat, resumeFrom)
+	if err != nil {
+		c.recordJSON("generate_stream", payload, nil, err)
+		return nil, err
+	}
+
+	path := c.nextPath("generate_stream", ".ndjson")
+	f, ferr := os.Create(path)
+	if ferr != nil {
+		// Recording is best-effort; don't fail the call over it.
+		return rc, nil
+	}
+	return &teeReadCloser{r: io.TeeReader(rc, f), src: rc, rec: f}, nil
+}
+
+func (c *recordingClient) recordJSON(name string, payload, resp map[string]interface{}, callErr error) {
+	rec := map[string]interface{}{"request": payload, "response": resp}
+	if callErr != nil {
+		rec["error"] = callErr.Error()
+	}
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(c.nextPath(name, ".json"), b, 0o644)
+}
+
+func (c *recordingClient) nextPath(name, ext string) string {
+	c.mu.Lock()
+	c.seq++
+	n := c.seq
+	c.mu.Unlock()
+	return filepath.Join(c.dir, fmt.Sprintf("%03d_%s%s", n, name, ext))
+}
+
+// teeReadCloser copies everything read from src into rec before handing
+// it to the caller, closing both on Close.
+type teeReadCloser struct {
+	r   io.Reader
+	src io.Closer
+	rec *os.File
+}
+
+func (t *teeReadCloser) Read(p []byte) (int, error) { return t.r.Read(p) }
+
+func (t *teeReadCloser) Close() error {
+	_ = t.rec.Close()
+	return t.src.Close()
+}
@@ -0,0 +1,182 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// Retry/circuit-breaker tuning for the resilient client.
+const (
+	retryBase  = 500 * time.Millisecond
+	retryCap   = 10 * time.Second
+	maxRetries = 4
+
+	circuitFailureThreshold = 5
+	circuitFailureWindow    = 60 * time.Second
+	circuitOpenDuration     = 30 * time.Second
+)
+
+// resilientClient wraps another Client with retry + backoff on transient
+// failures (network errors and 5xx responses; 4xx is treated as not
+// retryable) and a circuit breaker that, once tripped, short-circuits
+// further calls with the last-known-good response for that method rather
+// than hammering a backend that's already down.
+type resilientClient struct {
+	inner Client
+
+	mu        sync.Mutex
+	failures  []time.Time
+	openUntil time.Time
+	lastGood  map[string]map[string]interface{}
+}
+
+func newResilientClient(inner Client) *resilientClient {
+	return &resilientClient{inner: inner, lastGood: map[string]map[string]interface{}{}}
+}
+
+func (c *resilientClient) Generate(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.callJSON("generate", func() (map[string]interface{}, error) { return c.inner.Generate(payload) })
+}
+
+func (c *resilientClient) Questions(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.callJSON("questions", func() (map[string]interface{}, error) { return c.inner.Questions(payload) })
+}
+
+func (c *resilientClient) Overlay(payload map[string]interface{}) (map[string]interface{}, error) {
+	return c.callJSON("overlay", func() (map[string]interface{}, error) { return c.inner.Overlay(payload) })
+}
+
+func (c *resilientClient) GenerateStream(payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error) {
+	if !c.circuitAllows() {
+		return nil, fmt.Errorf("backend circuit open after repeated failures; try again shortly")
+	}
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryDelay(attempt))
+		}
+		rc, err := c.inner.GenerateStream(payload, streamFormat, resumeFrom)
+		if err == nil {
+			c.recordSuccess("generate_stream", nil)
+			return rc, nil
+		}
+		lastErr = err
+		c.recordFailure()
+		if !isRetryable(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// callJSON runs fn with retry + circuit breaker, falling back to the last
+// successful response for method when the circuit is open (without calling
+// fn) or when every attempt fails.
+func (c *resilientClient) callJSON(method string, fn func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	if !c.circuitAllows() {
+		if cached, ok := c.lastGoodFor(method); ok {
+			fmt.Fprintf(os.Stderr, "warning: backend circuit open; using last-known-good %s response\n", method)
+			return cached, nil
+		}
+		return nil, fmt.Errorf("backend circuit open after repeated failures; try again shortly")
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryDelay(attempt))
+		}
+		resp, err := fn()
+		if err == nil {
+			c.recordSuccess(method, resp)
+			return resp, nil
+		}
+		lastErr = err
+		c.recordFailure()
+		if !isRetryable(err) {
+			break
+		}
+	}
+
+	if cached, ok := c.lastGoodFor(method); ok {
+		fmt.Fprintf(os.Stderr, "warning: backend %s failed (%v); using last-known-good cached response\n", method, lastErr)
+		return cached, nil
+	}
+	return nil, lastErr
+}
+
+func (c *resilientClient) circuitAllows() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Now().After(c.openUntil)
+}
+
+func (c *resilientClient) recordFailure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	cutoff := now.Add(-circuitFailureWindow)
+	kept := c.failures[:0]
+	for _, t := range c.failures {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	c.failures = append(kept, now)
+	if len(c.failures) >= circuitFailureThreshold {
+		c.openUntil = now.Add(circuitOpenDuration)
+	}
+}
+
+func (c *resilientClient) recordSuccess(method string, resp map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = nil
+	if resp != nil {
+		c.lastGood[method] = resp
+	}
+}
+
+func (c *resilientClient) lastGoodFor(method string) (map[string]interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, ok := c.lastGood[method]
+	return resp, ok
+}
+
+// isRetryable reports whether err looks transient: a network-level error
+// (timeout, connection refused/reset) or a 5xx StatusError. 4xx responses
+// mean the request itself is bad and retrying won't help.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500
+	}
+	// Anything else (timeout, connection refused/reset, DNS failure) is
+	// a transport-level failure and treated as transient.
+	return true
+}
+
+// retryDelay returns the exponential backoff delay before retry attempt
+// (1-indexed): retryBase doubled per attempt, capped at retryCap, ±20%
+// jitter.
+func retryDelay(attempt int) time.Duration {
+	d := retryBase
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= retryCap {
+			d = retryCap
+			break
+		}
+	}
+	jitter := 1 + (rand.Float64()*0.4 - 0.2)
+	return time.Duration(float64(d) * jitter)
+}
@@ -0,0 +1,117 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+// fakeClient is an in-memory backend.Client so tests can drive the
+// resilient wrapper's retry/circuit-breaker logic without a real server.
+type fakeClient struct {
+	generateCalls int
+	generateErr   error
+	generateResp  map[string]interface{}
+}
+
+func (f *fakeClient) Generate(payload map[string]interface{}) (map[string]interface{}, error) {
+	f.generateCalls++
+	if f.generateErr != nil {
+		return nil, f.generateErr
+	}
+	return f.generateResp, nil
+}
+
+func (f *fakeClient) GenerateStream(payload map[string]interface{}, streamFormat, resumeFrom string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeClient) Overlay(payload map[string]interface{}) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeClient) Questions(payload map[string]interface{}) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"5xx status", &StatusError{Code: 503}, true},
+		{"4xx status", &StatusError{Code: 404}, false},
+		{"transport error", fmt.Errorf("connection reset by peer"), true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRetryable(c.err); got != c.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestResilientClient_FallsBackToLastGoodOnNonRetryableFailure(t *testing.T) {
+	fc := &fakeClient{generateResp: map[string]interface{}{"project_name": "demo"}}
+	c := newResilientClient(fc)
+
+	resp, err := c.Generate(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("first Generate: %v", err)
+	}
+	if resp["project_name"] != "demo" {
+		t.Fatalf("resp = %v, want project_name=demo", resp)
+	}
+
+	// A non-retryable failure shouldn't be retried (no sleeps), and should
+	// fall back to the cached last-known-good response.
+	fc.generateErr = &StatusError{Code: 400, Body: "bad request"}
+	resp, err = c.Generate(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("second Generate: %v, want fallback to last-known-good", err)
+	}
+	if resp["project_name"] != "demo" {
+		t.Errorf("resp = %v, want cached project_name=demo", resp)
+	}
+	if fc.generateCalls != 2 {
+		t.Errorf("generateCalls = %d, want 2 (no retries for a non-retryable error)", fc.generateCalls)
+	}
+}
+
+func TestResilientClient_CircuitOpensAfterRepeatedFailures(t *testing.T) {
+	fc := &fakeClient{generateErr: &StatusError{Code: 400, Body: "bad request"}}
+	c := newResilientClient(fc)
+
+	for i := 0; i < circuitFailureThreshold; i++ {
+		if _, err := c.Generate(map[string]interface{}{}); err == nil {
+			t.Fatalf("Generate call %d: got nil error, want failure (no last-known-good yet)", i)
+		}
+	}
+	if fc.generateCalls != circuitFailureThreshold {
+		t.Fatalf("generateCalls = %d, want %d", fc.generateCalls, circuitFailureThreshold)
+	}
+
+	// The circuit should now be open: the next call must fail immediately
+	// without reaching the inner client.
+	if _, err := c.Generate(map[string]interface{}{}); err == nil {
+		t.Fatal("Generate after threshold failures returned nil error, want circuit-open error")
+	}
+	if fc.generateCalls != circuitFailureThreshold {
+		t.Errorf("generateCalls = %d, want %d (circuit should short-circuit the call)", fc.generateCalls, circuitFailureThreshold)
+	}
+}
+
+func TestRetryDelay_BoundedAndCapped(t *testing.T) {
+	d := retryDelay(1)
+	if d < retryBase*8/10 || d > retryBase*12/10 {
+		t.Errorf("retryDelay(1) = %v, want within +/-20%% of retryBase (%v)", d, retryBase)
+	}
+
+	capped := retryDelay(10)
+	if capped < retryCap*8/10 || capped > retryCap*12/10 {
+		t.Errorf("retryDelay(10) = %v, want within +/-20%% of retryCap (%v)", capped, retryCap)
+	}
+}
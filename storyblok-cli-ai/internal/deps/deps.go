@@ -0,0 +1,282 @@
+// Package deps pins the overlay backend's new_dependencies into the
+// scaffold's package.json as real "^x.y.z" ranges instead of the "*"
+// placeholders cmd used to write, then runs the scaffold's package
+// manager to update its lockfile. Version resolution goes through the npm
+// registry when reachable and falls back to a bundled snapshot of common
+// package versions (see snapshot.go) when it isn't.
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// PinStrategy selects the semver range format Pin writes for a resolved
+// version.
+type PinStrategy string
+
+const (
+	// PinStrategyCaret writes "^x.y.z" (the default): pick up compatible
+	// minor/patch releases on the next install.
+	PinStrategyCaret PinStrategy = "caret"
+	// PinStrategyExact writes the bare "x.y.z": never move without a
+	// deliberate re-pin.
+	PinStrategyExact PinStrategy = "exact"
+	// PinStrategyRange writes ">=x.y.z": accept anything newer.
+	PinStrategyRange PinStrategy = "range"
+)
+
+// Options configures Pin.
+type Options struct {
+	// Offline skips the npm registry and resolves every package from the
+	// bundled snapshot.
+	Offline bool
+	// Registry overrides the npm registry base URL (for tests). Defaults
+	// to https://registry.npmjs.org.
+	Registry string
+	// PinStrategy controls the range format written for each pinned
+	// version. Defaults to PinStrategyCaret for an empty value.
+	PinStrategy PinStrategy
+	// Resolved, when set, is consulted before ResolveVersion: a name
+	// present here (typically from a LockfileResolve pass) is pinned to
+	// this exact version without a registry/snapshot lookup.
+	Resolved map[string]string
+}
+
+// Pinned describes one dependency Pin resolved and wrote.
+type Pinned struct {
+	Name     string
+	Version  string // the written range, e.g. "^1.2.3"
+	Resolved string // the bare resolved version, e.g. "1.2.3"
+	Dev      bool
+}
+
+// Result summarizes what Pin changed: packages newly added to
+// package.json versus ones that already had a (placeholder or stale)
+// entry that got replaced with a resolved version.
+type Result struct {
+	Added    []Pinned
+	Upgraded []Pinned
+}
+
+// devPackagePatterns identifies packages that belong under devDependencies
+// rather than dependencies: scoped type packages, known dev tooling, and
+// anything with a recognizable dev-tool prefix.
+var devPackagePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^@types/`),
+	regexp.MustCompile(`^eslint`),
+	regexp.MustCompile(`^prettier`),
+	regexp.MustCompile(`^vite`),
+	regexp.MustCompile(`^@vitejs/`),
+	regexp.MustCompile(`^vitest`),
+	regexp.MustCompile(`^typescript$`),
+	regexp.MustCompile(`^ts-node$`),
+	regexp.MustCompile(`^@testing-library/`),
+	regexp.MustCompile(`^jest`),
+	regexp.MustCompile(`^autoprefixer$`),
+	regexp.MustCompile(`^postcss$`),
+	regexp.MustCompile(`^tailwindcss$`),
+}
+
+// IsDevDependency reports whether name matches the heuristic list of
+// dev-only packages.
+func IsDevDependency(name string) bool {
+	for _, re := range devPackagePatterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pin resolves each name in newDeps to a concrete version and writes it
+// into pkgJSONPath under "dependencies" or "devDependencies" (per
+// IsDevDependency), skipping any name that already has a real semver
+// range (anything other than empty or the "*" placeholder). Names already
+// present as "*" are treated as unresolved and upgraded in place.
+func Pin(pkgJSONPath string, newDeps []string, opts Options) (Result, error) {
+	var result Result
+
+	raw, err := os.ReadFile(pkgJSONPath)
+	if err != nil {
+		return result, fmt.Errorf("read %s: %w", pkgJSONPath, err)
+	}
+	var pj map[string]interface{}
+	if err := json.Unmarshal(raw, &pj); err != nil {
+		return result, fmt.Errorf("parse %s: %w", pkgJSONPath, err)
+	}
+
+	nodeEngine := ""
+	if engines, ok := pj["engines"].(map[string]interface{}); ok {
+		if n, ok := engines["node"].(string); ok {
+			nodeEngine = n
+		}
+	}
+
+	deps, _ := pj["dependencies"].(map[string]interface{})
+	if deps == nil {
+		deps = map[string]interface{}{}
+	}
+	devDeps, _ := pj["devDependencies"].(map[string]interface{})
+	if devDeps == nil {
+		devDeps = map[string]interface{}{}
+	}
+
+	for _, name := range newDeps {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		existing, alreadyDev := devDeps[name]
+		if !alreadyDev {
+			existing, _ = deps[name]
+		}
+		if v, ok := existing.(string); ok && v != "" && v != "*" {
+			// Already pinned to a real range; leave it alone.
+			continue
+		}
+
+		version, ok := opts.Resolved[name]
+		if !ok {
+			var rerr error
+			version, rerr = ResolveVersion(name, nodeEngine, opts)
+			if rerr != nil {
+				return result, fmt.Errorf("resolve %s: %w", name, rerr)
+			}
+		}
+		pinned := Pinned{Name: name, Version: formatPinnedVersion(version, opts.PinStrategy), Resolved: version, Dev: IsDevDependency(name)}
+
+		if pinned.Dev {
+			delete(deps, name)
+			devDeps[name] = pinned.Version
+		} else {
+			delete(devDeps, name)
+			deps[name] = pinned.Version
+		}
+
+		if existing != nil {
+			result.Upgraded = append(result.Upgraded, pinned)
+		} else {
+			result.Added = append(result.Added, pinned)
+		}
+	}
+
+	pj["dependencies"] = deps
+	pj["devDependencies"] = devDeps
+
+	out, err := json.MarshalIndent(pj, "", "  ")
+	if err != nil {
+		return result, fmt.Errorf("marshal %s: %w", pkgJSONPath, err)
+	}
+	if err := os.WriteFile(pkgJSONPath, out, 0o644); err != nil {
+		return result, fmt.Errorf("write %s: %w", pkgJSONPath, err)
+	}
+	return result, nil
+}
+
+// Summary renders a short human-readable report of what Pin changed, for
+// printing after install completes.
+func (r Result) Summary() string {
+	if len(r.Added) == 0 && len(r.Upgraded) == 0 {
+		return "no dependencies to pin"
+	}
+	var b strings.Builder
+	if len(r.Added) > 0 {
+		fmt.Fprintf(&b, "added: ")
+		for i, p := range r.Added {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%s@%s", p.Name, p.Version)
+		}
+	}
+	if len(r.Upgraded) > 0 {
+		if len(r.Added) > 0 {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "upgraded: ")
+		for i, p := range r.Upgraded {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%s@%s", p.Name, p.Version)
+		}
+	}
+	return b.String()
+}
+
+// ByName indexes every Pinned Pin produced (Added and Upgraded) by name,
+// for callers that need to look up what version a specific package was
+// resolved to, e.g. cross-checking against a backend-suggested version
+// with CheckSuggestions.
+func (r Result) ByName() map[string]Pinned {
+	m := make(map[string]Pinned, len(r.Added)+len(r.Upgraded))
+	for _, p := range r.Added {
+		m[p.Name] = p
+	}
+	for _, p := range r.Upgraded {
+		m[p.Name] = p
+	}
+	return m
+}
+
+// formatPinnedVersion renders version per strategy, defaulting to
+// PinStrategyCaret for an empty or unrecognized strategy.
+func formatPinnedVersion(version string, strategy PinStrategy) string {
+	switch strategy {
+	case PinStrategyExact:
+		return version
+	case PinStrategyRange:
+		return ">=" + version
+	default:
+		return "^" + version
+	}
+}
+
+// Suggestion is one package version the overlay backend suggested while
+// streaming generated files (see the create wizard's "dependency" stream
+// event), kept around so it can be cross-checked against what Pin actually
+// resolved.
+type Suggestion struct {
+	Name       string
+	Version    string
+	Confidence float64
+}
+
+// VersionWarning flags a backend-suggested version that disagreed with
+// what Pin resolved for the same package.
+type VersionWarning struct {
+	Name       string
+	Suggested  string
+	Resolved   string
+	Confidence float64
+}
+
+func (w VersionWarning) String() string {
+	return fmt.Sprintf("%s: backend suggested %s (confidence %.2f) but the resolver picked %s", w.Name, w.Suggested, w.Confidence, w.Resolved)
+}
+
+// CheckSuggestions compares each backend-suggested package version against
+// what Pin actually resolved and returns a warning for every package whose
+// suggested version disagrees with the resolved one at or above
+// confidenceThreshold. A low-confidence disagreement is expected (that's
+// why the backend streams a confidence score at all); a high-confidence
+// one suggests the backend's training data is stale against the registry
+// or lockfile.
+func CheckSuggestions(suggestions []Suggestion, resolved map[string]Pinned, confidenceThreshold float64) []VersionWarning {
+	var warnings []VersionWarning
+	for _, s := range suggestions {
+		if s.Version == "" || s.Confidence < confidenceThreshold {
+			continue
+		}
+		p, ok := resolved[s.Name]
+		if !ok || p.Resolved == s.Version {
+			continue
+		}
+		warnings = append(warnings, VersionWarning{Name: s.Name, Suggested: s.Version, Resolved: p.Resolved, Confidence: s.Confidence})
+	}
+	return warnings
+}
@@ -0,0 +1,57 @@
+package deps
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// Install runs the package manager's install command in dir, updating its
+// lockfile for the versions Pin just wrote, and reports progress through
+// an indeterminate progressbar spinner (one tick per line of output),
+// matching the one the create wizard already uses while streaming
+// generated files.
+func Install(dir, packageManager string) error {
+	args := []string{"install"}
+	bin := packageManager
+	if bin == "" {
+		bin = "npm"
+	}
+
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = dir
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("pipe %s install stdout: %w", bin, err)
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s install: %w", bin, err)
+	}
+
+	pb := progressbar.NewOptions(-1,
+		progressbar.OptionSetDescription(fmt.Sprintf("Running %s install", bin)),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSpinnerType(14),
+	)
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		_ = pb.Add(1)
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		_ = pb.Finish()
+		_ = cmd.Wait()
+		return fmt.Errorf("read %s install output: %w", bin, err)
+	}
+	_ = pb.Finish()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s install failed: %w", bin, err)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// lockfileNames maps a package manager to the lockfile it reads and writes.
+var lockfileNames = map[string]string{
+	"npm":  "package-lock.json",
+	"pnpm": "pnpm-lock.yaml",
+	"yarn": "yarn.lock",
+}
+
+// LockfileResolve is the authoritative companion to ResolveVersion: instead
+// of asking the npm registry what a package's "latest" dist-tag is, it asks
+// the scaffold's own package manager to resolve versions the way a real
+// install would, by running its lockfile-only mode in a throwaway sandbox
+// copy of package.json (seeded with the scaffold's existing lockfile, if
+// any, so the resolution is incremental) and reading back whatever
+// lockfile it writes. This is slower than ResolveVersion but matches what
+// `install` will actually pin, including constraints (peer deps, engines,
+// an existing lockfile's resolutions) ResolveVersion can only approximate
+// from the registry alone. An unsupported package manager, a failed
+// install, or a lockfile that can't be read or parsed is returned as an
+// error. Names missing from a lockfile that did parse are simply absent
+// from the returned map, for Pin to fall back to ResolveVersion on.
+func LockfileResolve(scaffoldDir, packageManager string, names []string) (map[string]string, error) {
+	if len(names) == 0 {
+		return map[string]string{}, nil
+	}
+	bin := packageManager
+	if bin == "" {
+		bin = "npm"
+	}
+	lockfileName, ok := lockfileNames[bin]
+	if !ok {
+		return nil, fmt.Errorf("lockfile resolution: unsupported package manager %q", bin)
+	}
+
+	sandbox, err := os.MkdirTemp("", "ai_deps_lockfile_*")
+	if err != nil {
+		return nil, fmt.Errorf("create lockfile resolution sandbox: %w", err)
+	}
+	defer os.RemoveAll(sandbox)
+
+	if err := seedSandboxManifest(scaffoldDir, sandbox, names); err != nil {
+		return nil, err
+	}
+	copyIfExists(filepath.Join(scaffoldDir, lockfileName), filepath.Join(sandbox, lockfileName))
+
+	var cmd *exec.Cmd
+	switch bin {
+	case "npm":
+		cmd = exec.Command("npm", "install", "--package-lock-only", "--ignore-scripts")
+	case "pnpm":
+		cmd = exec.Command("pnpm", "install", "--lockfile-only", "--ignore-scripts")
+	case "yarn":
+		cmd = exec.Command("yarn", "install", "--mode=update-lockfile")
+	}
+	cmd.Dir = sandbox
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s lockfile resolution failed: %w\n%s", bin, err, out)
+	}
+
+	lockData, err := os.ReadFile(filepath.Join(sandbox, lockfileName))
+	if err != nil {
+		return nil, fmt.Errorf("read resolved %s: %w", lockfileName, err)
+	}
+
+	var versions map[string]string
+	switch bin {
+	case "npm":
+		versions, err = parseNpmLockfile(lockData)
+		if err != nil {
+			return nil, err
+		}
+	case "pnpm":
+		versions = parsePnpmLockfile(lockData)
+	case "yarn":
+		versions = parseYarnLockfile(lockData)
+	}
+
+	resolved := make(map[string]string, len(names))
+	for _, name := range names {
+		if v, ok := versions[name]; ok {
+			resolved[name] = v
+		}
+	}
+	return resolved, nil
+}
+
+// seedSandboxManifest writes a package.json into sandbox that's a copy of
+// scaffoldDir's own, except every name in names is added to
+// "dependencies" as "*" if it isn't already pinned to a real range — the
+// placeholder the package manager will resolve during the lockfile-only
+// install.
+func seedSandboxManifest(scaffoldDir, sandbox string, names []string) error {
+	raw, err := os.ReadFile(filepath.Join(scaffoldDir, "package.json"))
+	if err != nil {
+		return fmt.Errorf("read scaffold package.json: %w", err)
+	}
+	var pj map[string]interface{}
+	if err := json.Unmarshal(raw, &pj); err != nil {
+		return fmt.Errorf("parse scaffold package.json: %w", err)
+	}
+
+	deps, _ := pj["dependencies"].(map[string]interface{})
+	if deps == nil {
+		deps = map[string]interface{}{}
+	}
+	devDeps, _ := pj["devDependencies"].(map[string]interface{})
+
+	for _, name := range names {
+		if v, ok := devDeps[name].(string); ok && v != "" && v != "*" {
+			continue
+		}
+		if v, ok := deps[name].(string); ok && v != "" && v != "*" {
+			continue
+		}
+		deps[name] = "*"
+	}
+	pj["dependencies"] = deps
+
+	out, err := json.MarshalIndent(pj, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sandbox package.json: %w", err)
+	}
+	return os.WriteFile(filepath.Join(sandbox, "package.json"), out, 0o644)
+}
+
+// copyIfExists copies src to dst, silently doing nothing if src doesn't
+// exist (a scaffold without a lockfile yet is the normal case for a fresh
+// `create` run).
+func copyIfExists(src, dst string) {
+	in, err := os.Open(src)
+	if err != nil {
+		return
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return
+	}
+	defer out.Close()
+	_, _ = io.Copy(out, in)
+}
+
+// npmLockPackage is the subset of an npm v2/v3 lockfile's "packages" (or
+// v1 "dependencies") entry LockfileResolve cares about.
+type npmLockPackage struct {
+	Version string `json:"version"`
+}
+
+// parseNpmLockfile extracts name -> resolved version from a
+// package-lock.json, handling both the v2/v3 "packages" layout (keyed by
+// "node_modules/<name>", nested for transitive deps) and the older v1
+// "dependencies" layout (keyed directly by name).
+func parseNpmLockfile(data []byte) (map[string]string, error) {
+	var lf struct {
+		Packages     map[string]npmLockPackage `json:"packages"`
+		Dependencies map[string]npmLockPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lf); err != nil {
+		return nil, fmt.Errorf("parse package-lock.json: %w", err)
+	}
+
+	versions := make(map[string]string, len(lf.Packages)+len(lf.Dependencies))
+	for key, pkg := range lf.Packages {
+		if key == "" || pkg.Version == "" {
+			continue
+		}
+		idx := strings.LastIndex(key, "node_modules/")
+		if idx == -1 {
+			continue
+		}
+		name := key[idx+len("node_modules/"):]
+		versions[name] = pkg.Version
+	}
+	for name, pkg := range lf.Dependencies {
+		if pkg.Version == "" {
+			continue
+		}
+		if _, ok := versions[name]; !ok {
+			versions[name] = pkg.Version
+		}
+	}
+	return versions, nil
+}
+
+// pnpmPackageNameRe/pnpmVersionRe do a deliberately simplified line-based
+// read of pnpm-lock.yaml's top-level dependencies/devDependencies blocks —
+// adequate for extracting the resolved version of each direct dependency,
+// not a general YAML parser (no workspace/catalog protocol support).
+var (
+	pnpmPackageNameRe = regexp.MustCompile(`^  (\S+):\s*$`)
+	pnpmVersionRe     = regexp.MustCompile(`^    version:\s*([^\s(]+)`)
+)
+
+func parsePnpmLockfile(data []byte) map[string]string {
+	versions := map[string]string{}
+	current := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := pnpmPackageNameRe.FindStringSubmatch(line); m != nil {
+			current = strings.Trim(m[1], `'"`)
+			continue
+		}
+		if current == "" {
+			continue
+		}
+		if m := pnpmVersionRe.FindStringSubmatch(line); m != nil {
+			versions[current] = m[1]
+			current = ""
+		}
+	}
+	return versions
+}
+
+// yarnNameRe/yarnVersionRe do the same deliberately simplified read for
+// yarn.lock's custom (non-YAML, non-JSON) format: a header line per
+// package (possibly several comma-separated specifiers sharing one
+// resolution) followed by an indented `version "x.y.z"` line.
+var (
+	yarnNameRe    = regexp.MustCompile(`^"?(@[^/]+/[^@"]+|[^@"]+)@`)
+	yarnVersionRe = regexp.MustCompile(`^  version "?([^"\s]+)"?`)
+)
+
+func parseYarnLockfile(data []byte) map[string]string {
+	versions := map[string]string{}
+	current := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			current = ""
+			if strings.HasSuffix(line, ":") {
+				firstSpec := strings.TrimSpace(strings.SplitN(line, ",", 2)[0])
+				if m := yarnNameRe.FindStringSubmatch(firstSpec); m != nil {
+					current = m[1]
+				}
+			}
+			continue
+		}
+		if current == "" {
+			continue
+		}
+		if m := yarnVersionRe.FindStringSubmatch(line); m != nil {
+			versions[current] = m[1]
+			current = ""
+		}
+	}
+	return versions
+}
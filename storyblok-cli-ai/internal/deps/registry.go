@@ -0,0 +1,142 @@
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const defaultRegistry = "https://registry.npmjs.org"
+
+// registryPackage is the subset of an npm registry package document Pin
+// cares about: the "latest" dist-tag and, per published version, the
+// engines.node range it declares (most packages omit it).
+type registryPackage struct {
+	DistTags struct {
+		Latest string `json:"latest"`
+	} `json:"dist-tags"`
+	Versions map[string]struct {
+		Engines struct {
+			Node string `json:"node"`
+		} `json:"engines"`
+	} `json:"versions"`
+}
+
+// ResolveVersion returns the version (without a leading "^"/">=") to pin
+// name to: the newest published version whose declared engines.node is
+// compatible with nodeEngine (the scaffold's own package.json "engines.node"
+// range, e.g. ">=18"), or the registry's "latest" dist-tag if nodeEngine is
+// empty or no version declares an engines.node constraint. Falls back to
+// the bundled snapshot when opts.Offline is set or the registry request
+// fails.
+func ResolveVersion(name, nodeEngine string, opts Options) (string, error) {
+	if opts.Offline {
+		return snapshotVersion(name)
+	}
+
+	registry := opts.Registry
+	if registry == "" {
+		registry = defaultRegistry
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(registry + "/" + name)
+	if err != nil {
+		if v, serr := snapshotVersion(name); serr == nil {
+			return v, nil
+		}
+		return "", fmt.Errorf("fetch %s from registry: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		if v, serr := snapshotVersion(name); serr == nil {
+			return v, nil
+		}
+		return "", fmt.Errorf("registry returned status %d for %s", resp.StatusCode, name)
+	}
+
+	var pkg registryPackage
+	if err := json.NewDecoder(resp.Body).Decode(&pkg); err != nil {
+		return "", fmt.Errorf("parse registry response for %s: %w", name, err)
+	}
+	if pkg.DistTags.Latest == "" {
+		return "", fmt.Errorf("registry has no latest dist-tag for %s", name)
+	}
+	if nodeEngine == "" {
+		return pkg.DistTags.Latest, nil
+	}
+
+	requiredMajor, ok := minMajorVersion(nodeEngine)
+	if !ok {
+		return pkg.DistTags.Latest, nil
+	}
+
+	versions := make([]string, 0, len(pkg.Versions))
+	for v := range pkg.Versions {
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(byVersion(versions)))
+
+	for _, v := range versions {
+		engineNode := pkg.Versions[v].Engines.Node
+		if engineNode == "" {
+			continue
+		}
+		if pkgMajor, ok := minMajorVersion(engineNode); ok && requiredMajor >= pkgMajor {
+			return v, nil
+		}
+	}
+	// No version declared an engines.node we could check against the
+	// scaffold's node engine; latest is the best guess.
+	return pkg.DistTags.Latest, nil
+}
+
+// versionParts splits "1.2.3" (ignoring any pre-release/build suffix)
+// into its numeric components, for comparison and engine-range parsing.
+var versionComponent = regexp.MustCompile(`\d+`)
+
+func versionParts(v string) []int {
+	matches := versionComponent.FindAllString(v, 3)
+	parts := make([]int, 0, len(matches))
+	for _, m := range matches {
+		n, err := strconv.Atoi(m)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// minMajorVersion extracts the major version number a semver range like
+// ">=18.0.0", "^18", "~18.4", or a bare "18" requires at minimum. This is
+// a deliberately simplified semver reading (no exact upper-bound/OR-range
+// support) adequate for the major-version-only compatibility check above.
+func minMajorVersion(rangeExpr string) (int, bool) {
+	parts := versionParts(rangeExpr)
+	if len(parts) == 0 {
+		return 0, false
+	}
+	return parts[0], true
+}
+
+// byVersion sorts dotted version strings numerically component-by-component.
+type byVersion []string
+
+func (b byVersion) Len() int      { return len(b) }
+func (b byVersion) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byVersion) Less(i, j int) bool {
+	pi, pj := versionParts(b[i]), versionParts(b[j])
+	for k := 0; k < len(pi) && k < len(pj); k++ {
+		if pi[k] != pj[k] {
+			return pi[k] < pj[k]
+		}
+	}
+	return len(pi) < len(pj)
+}
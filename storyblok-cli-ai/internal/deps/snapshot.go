@@ -0,0 +1,50 @@
+package deps
+
+import "fmt"
+
+// snapshotVersions is a small bundled table of known-good versions for the
+// packages the overlay backend most commonly suggests, used when the npm
+// registry can't be reached (offline dev, sandboxed CI). It's a practical
+// subset rather than an exhaustive registry mirror; ResolveVersion falls
+// back to it only after a live lookup fails or --offline-deps is set.
+var snapshotVersions = map[string]string{
+	"react":                  "18.3.1",
+	"react-dom":              "18.3.1",
+	"react-router-dom":       "6.26.1",
+	"next":                   "14.2.5",
+	"vue":                    "3.4.38",
+	"vue-router":             "4.4.3",
+	"nuxt":                   "3.13.0",
+	"svelte":                 "4.2.19",
+	"axios":                  "1.7.7",
+	"zustand":                "4.5.5",
+	"@tanstack/react-query":  "5.56.2",
+	"storyblok-js-client":    "6.7.4",
+	"@storyblok/react":       "3.2.0",
+	"@storyblok/vue":         "8.0.8",
+	"@storyblok/nuxt":        "6.1.2",
+	"tailwindcss":            "3.4.10",
+	"autoprefixer":           "10.4.20",
+	"postcss":                "8.4.45",
+	"typescript":             "5.5.4",
+	"vite":                   "5.4.3",
+	"@vitejs/plugin-react":   "4.3.1",
+	"vitest":                 "2.0.5",
+	"eslint":                 "8.57.0",
+	"prettier":               "3.3.3",
+	"@types/react":           "18.3.5",
+	"@types/react-dom":       "18.3.0",
+	"@types/node":            "20.16.5",
+	"ts-node":                "10.9.2",
+	"jest":                   "29.7.0",
+	"@testing-library/react": "16.0.1",
+}
+
+// snapshotVersion looks up name in the bundled snapshot.
+func snapshotVersion(name string) (string, error) {
+	v, ok := snapshotVersions[name]
+	if !ok {
+		return "", fmt.Errorf("%s not in offline snapshot", name)
+	}
+	return v, nil
+}
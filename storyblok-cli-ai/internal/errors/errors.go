@@ -0,0 +1,95 @@
+// Package errors wraps the stdlib errors package with a small
+// emperror.dev/errors-style addition: WithStackIf captures the caller's
+// stack trace the first time an error is wrapped, and leaves it alone on
+// every subsequent wrap so a deep call chain doesn't pile up redundant
+// frames. It also defines the sentinel errors shared across scaffold and
+// cmd so callers can errors.Is/errors.As against them.
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Re-exported so callers only need to import this package.
+var (
+	Is     = errors.Is
+	As     = errors.As
+	New    = errors.New
+	Unwrap = errors.Unwrap
+)
+
+// Sentinel errors. Wrap these with fmt.Errorf("...: %w", ErrX) and pass the
+// result through WithStackIf; errors.Is/As continues to work through the
+// wrapping.
+var (
+	ErrTargetExists       = errors.New("target already exists")
+	ErrPathTraversal      = errors.New("path traversal not allowed")
+	ErrBackendUnavailable = errors.New("backend unavailable")
+	ErrDependencyMissing  = errors.New("required dependency not installed")
+	ErrVersionMismatch    = errors.New("installed dependency version does not match the pinned version")
+)
+
+// ErrBackendStatus reports a non-2xx HTTP response from a backend call.
+type ErrBackendStatus struct {
+	Code int
+	Body string
+}
+
+func (e *ErrBackendStatus) Error() string {
+	return fmt.Sprintf("backend returned status %d: %s", e.Code, e.Body)
+}
+
+// stackTracer is implemented by errors that carry a captured stack trace.
+type stackTracer interface {
+	StackTrace() string
+}
+
+type withStack struct {
+	err    error
+	frames []uintptr
+}
+
+func (w *withStack) Error() string { return w.err.Error() }
+func (w *withStack) Unwrap() error { return w.err }
+
+func (w *withStack) StackTrace() string {
+	frames := runtime.CallersFrames(w.frames)
+	var b strings.Builder
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "\t%s\n\t\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// WithStackIf wraps err with a stack trace captured at the caller, unless
+// err (or something it wraps) already carries one. Returns nil for a nil
+// err so it's safe to call unconditionally at a function's return site.
+func WithStackIf(err error) error {
+	if err == nil {
+		return nil
+	}
+	var st stackTracer
+	if errors.As(err, &st) {
+		return err
+	}
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &withStack{err: err, frames: pcs[:n]}
+}
+
+// StackTrace returns the stack trace attached to err via WithStackIf, if
+// any.
+func StackTrace(err error) (string, bool) {
+	var st stackTracer
+	if errors.As(err, &st) {
+		return st.StackTrace(), true
+	}
+	return "", false
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWithStackIfNil(t *testing.T) {
+	if err := WithStackIf(nil); err != nil {
+		t.Fatalf("WithStackIf(nil) = %v, want nil", err)
+	}
+}
+
+func TestWithStackIfAttachesStackOnce(t *testing.T) {
+	base := fmt.Errorf("%w: storyblok CLI", ErrDependencyMissing)
+	wrapped := WithStackIf(base)
+
+	trace, ok := StackTrace(wrapped)
+	if !ok || trace == "" {
+		t.Fatalf("StackTrace(wrapped) = %q, %v; want a non-empty trace", trace, ok)
+	}
+	if !strings.Contains(trace, "errors_test.go") {
+		t.Errorf("StackTrace(wrapped) = %q, want it to include the caller frame", trace)
+	}
+
+	// Wrapping again must not replace the already-captured trace.
+	rewrapped := WithStackIf(fmt.Errorf("outer: %w", wrapped))
+	trace2, ok := StackTrace(rewrapped)
+	if !ok {
+		t.Fatal("StackTrace(rewrapped) = false, want true")
+	}
+	if trace2 != trace {
+		t.Errorf("re-wrapping replaced the stack trace:\nfirst:  %q\nsecond: %q", trace, trace2)
+	}
+}
+
+func TestWithStackIfPreservesSentinelMatching(t *testing.T) {
+	err := WithStackIf(fmt.Errorf("install storyblok CLI: %w", ErrVersionMismatch))
+	if !Is(err, ErrVersionMismatch) {
+		t.Errorf("Is(err, ErrVersionMismatch) = false, want true")
+	}
+
+	var statusErr *ErrBackendStatus
+	wrappedStatus := WithStackIf(fmt.Errorf("call failed: %w", &ErrBackendStatus{Code: 503, Body: "down"}))
+	if !As(wrappedStatus, &statusErr) {
+		t.Fatalf("As(wrappedStatus, &statusErr) = false, want true")
+	}
+	if statusErr.Code != 503 {
+		t.Errorf("statusErr.Code = %d, want 503", statusErr.Code)
+	}
+}
+
+func TestStackTraceMissing(t *testing.T) {
+	if _, ok := StackTrace(ErrTargetExists); ok {
+		t.Error("StackTrace(ErrTargetExists) = true, want false for an error never passed through WithStackIf")
+	}
+}
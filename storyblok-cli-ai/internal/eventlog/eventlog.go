@@ -0,0 +1,154 @@
+// Package eventlog gives the create-wizard streaming loop a structured
+// log/slog logger and, separately, a JSONL tee of every raw
+// file_start/file_chunk/file_complete/dependency/warning event it
+// receives from the backend. The tee (opened via --event-log) is a
+// standalone recording independent of ~/.storyblok-ai-cli's session log
+// and stream checkpoint/journal: replaying it with
+// `storyblok-cli-ai scaffold replay` reconstructs the scaffold's files
+// without contacting the backend at all, which is what lets CI
+// snapshot-test the CLI against a recorded stream.
+package eventlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Entry is one line of an --event-log file: the raw event plus enough
+// bookkeeping (event id, round, timestamp, byte offset) to replay it, or
+// diff two recordings of the same session.
+type Entry struct {
+	Event      string          `json:"event"`
+	EventID    string          `json:"event_id,omitempty"`
+	Round      int             `json:"round"`
+	Timestamp  time.Time       `json:"timestamp"`
+	ByteOffset int64           `json:"byte_offset"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// Recorder appends Entries to an --event-log file. A nil *Recorder is
+// valid and every method is a no-op, matching session.Recorder so callers
+// that don't have --event-log set don't need to guard every call site.
+type Recorder struct {
+	f      *os.File
+	offset int64
+}
+
+// Open creates (or truncates) path for a fresh event log. Truncating
+// rather than appending keeps one file per run unambiguous to replay,
+// unlike the session log and stream journal, which are meant to survive
+// reconnects and resumes of the *same* run.
+func Open(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	return &Recorder{f: f}, nil
+}
+
+// Record appends one entry. payload is marshaled to JSON; a marshal or
+// write failure is reported to stderr rather than returned, so a logging
+// problem never aborts the stream. The byte offset recorded is the
+// running total of marshaled payload bytes tee'd so far across all
+// events; it is not a position within any one generated file.
+func (r *Recorder) Record(event, eventID string, round int, payload interface{}) {
+	if r == nil {
+		return
+	}
+	pb, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to marshal event-log entry: %v\n", err)
+		return
+	}
+	entry := Entry{
+		Event:      event,
+		EventID:    eventID,
+		Round:      round,
+		Timestamp:  time.Now().UTC(),
+		ByteOffset: r.offset,
+		Payload:    pb,
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to marshal event-log entry: %v\n", err)
+		return
+	}
+	if _, err := r.f.Write(append(b, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write event-log entry: %v\n", err)
+	}
+	r.offset += int64(len(pb))
+}
+
+// Close closes the underlying file.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	return r.f.Close()
+}
+
+// Load reads every entry from an --event-log file, in order, for
+// `scaffold replay`.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 8*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("parse event log: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read event log: %w", err)
+	}
+	return entries, nil
+}
+
+// NewLogger returns a slog.Logger writing to stderr, using a JSON handler
+// when format is "json" and a human-readable text handler otherwise (the
+// default), matching --log-format on `create`/`replay`/`scaffold replay`.
+func NewLogger(format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	var h slog.Handler
+	if format == "json" {
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		h = slog.NewTextHandler(os.Stderr, opts)
+	}
+	return slog.New(h)
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger, for the handful of
+// call sites (deep in the stream event handlers) too far from the command
+// layer to have it passed explicitly.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stashed by WithLogger, or slog.Default()
+// if none was set.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
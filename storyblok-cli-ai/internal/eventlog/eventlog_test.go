@@ -0,0 +1,79 @@
+package eventlog
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenRecordLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	rec, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	rec.Record("file_start", "ev-1", 1, map[string]interface{}{"path": "src/index.ts"})
+	rec.Record("file_complete", "ev-2", 1, map[string]interface{}{"path": "src/index.ts", "bytes": 42})
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+	if entries[0].Event != "file_start" || entries[0].EventID != "ev-1" || entries[0].Round != 1 {
+		t.Errorf("entries[0] = %+v, want file_start/ev-1/round 1", entries[0])
+	}
+	if entries[1].Event != "file_complete" || entries[1].EventID != "ev-2" {
+		t.Errorf("entries[1] = %+v, want file_complete/ev-2", entries[1])
+	}
+	// ByteOffset should advance between entries, tracking the running total
+	// of tee'd payload bytes.
+	if entries[1].ByteOffset <= entries[0].ByteOffset {
+		t.Errorf("entries[1].ByteOffset = %d, want > entries[0].ByteOffset = %d", entries[1].ByteOffset, entries[0].ByteOffset)
+	}
+}
+
+func TestOpenTruncatesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	rec1, err := Open(path)
+	if err != nil {
+		t.Fatalf("first Open: %v", err)
+	}
+	rec1.Record("warning", "", 1, map[string]interface{}{"text": "stale"})
+	rec1.Close()
+
+	rec2, err := Open(path)
+	if err != nil {
+		t.Fatalf("second Open: %v", err)
+	}
+	rec2.Record("warning", "", 1, map[string]interface{}{"text": "fresh"})
+	rec2.Close()
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1 (Open should truncate, not append)", len(entries))
+	}
+}
+
+func TestNilRecorderIsANoop(t *testing.T) {
+	var rec *Recorder
+	rec.Record("warning", "", 1, map[string]interface{}{"text": "ignored"})
+	if err := rec.Close(); err != nil {
+		t.Errorf("nil Recorder Close() = %v, want nil", err)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("Load(missing file) = nil error, want an error")
+	}
+}
@@ -0,0 +1,161 @@
+// Package filecache is a small content-addressable store keyed by
+// md5(path+content), used to avoid rewriting overlay files whose contents
+// haven't changed between wizard rounds. Blobs live under
+// ~/.storyblok-ai-cli/cache/blobs, tracked by a JSON manifest alongside
+// them.
+package filecache
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Hash returns the content-addressable key for a file's path and content.
+func Hash(path, content string) string {
+	h := md5.Sum([]byte(path + content))
+	return hex.EncodeToString(h[:])
+}
+
+type manifestEntry struct {
+	BlobPath  string    `json:"blob_path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type manifest struct {
+	Entries map[string]manifestEntry `json:"entries"`
+}
+
+// Cache is a handle to the on-disk blob store and its manifest.
+type Cache struct {
+	manifestPath string
+	blobsDir     string
+}
+
+// New opens the cache rooted at ~/.storyblok-ai-cli/cache, creating it if
+// it doesn't exist yet.
+func New() (*Cache, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("determine home dir: %w", err)
+	}
+	dir := filepath.Join(home, ".storyblok-ai-cli", "cache")
+	blobsDir := filepath.Join(dir, "blobs")
+	if err := os.MkdirAll(blobsDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return &Cache{manifestPath: filepath.Join(dir, "manifest.json"), blobsDir: blobsDir}, nil
+}
+
+func (c *Cache) loadManifest() (*manifest, error) {
+	m := &manifest{Entries: map[string]manifestEntry{}}
+	b, err := os.ReadFile(c.manifestPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	if m.Entries == nil {
+		m.Entries = map[string]manifestEntry{}
+	}
+	return m, nil
+}
+
+func (c *Cache) saveManifest(m *manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.manifestPath, b, 0o644)
+}
+
+// Has reports whether a blob for hash is present both in the manifest and
+// on disk.
+func (c *Cache) Has(hash string) bool {
+	m, err := c.loadManifest()
+	if err != nil {
+		return false
+	}
+	entry, ok := m.Entries[hash]
+	if !ok {
+		return false
+	}
+	_, err = os.Stat(entry.BlobPath)
+	return err == nil
+}
+
+// Put stores content under hash, skipping the write if it's already
+// cached.
+func (c *Cache) Put(hash, content string) error {
+	m, err := c.loadManifest()
+	if err != nil {
+		return err
+	}
+	if entry, ok := m.Entries[hash]; ok {
+		if _, statErr := os.Stat(entry.BlobPath); statErr == nil {
+			return nil
+		}
+	}
+	blobPath := filepath.Join(c.blobsDir, hash)
+	if err := os.WriteFile(blobPath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write blob: %w", err)
+	}
+	m.Entries[hash] = manifestEntry{BlobPath: blobPath, CreatedAt: time.Now()}
+	return c.saveManifest(m)
+}
+
+// CopyTo hardlinks the cached blob for hash to target, falling back to a
+// plain copy when hardlinking isn't possible (e.g. across devices).
+// target's parent directories are created as needed.
+func (c *Cache) CopyTo(hash, target string) error {
+	m, err := c.loadManifest()
+	if err != nil {
+		return err
+	}
+	entry, ok := m.Entries[hash]
+	if !ok {
+		return fmt.Errorf("no cached blob for hash %s", hash)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	_ = os.Remove(target)
+	if err := os.Link(entry.BlobPath, target); err == nil {
+		return nil
+	}
+	b, err := os.ReadFile(entry.BlobPath)
+	if err != nil {
+		return fmt.Errorf("read blob: %w", err)
+	}
+	return os.WriteFile(target, b, 0o644)
+}
+
+// Prune removes every cached blob older than maxAge and returns how many
+// were removed.
+func (c *Cache) Prune(maxAge time.Duration) (int, error) {
+	m, err := c.loadManifest()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for hash, entry := range m.Entries {
+		if entry.CreatedAt.Before(cutoff) {
+			_ = os.Remove(entry.BlobPath)
+			delete(m.Entries, hash)
+			removed++
+		}
+	}
+	if err := c.saveManifest(m); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
@@ -0,0 +1,208 @@
+// Package formatters resolves and runs the code formatter for a generated
+// file via a registry loaded from ~/.storyblok-cli/formatters.yaml (or a
+// built-in default when that file doesn't exist), replacing the old
+// hard-coded npx prettier/black/gofmt calls that silently no-op when the
+// tool is missing. Each formatter command is run as a subprocess that
+// receives the file's content on stdin and returns the formatted result on
+// stdout, so formatting never needs the file to exist on the local
+// filesystem -- the same content in memory that flows to a tar/ftp/sftp/s3
+// --output target.
+package formatters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTimeout bounds a formatter command when its Rule doesn't set one.
+const defaultTimeout = 15 * time.Second
+
+// Candidate is one formatter command a Rule may try. Rules list Candidates
+// in priority order -- e.g. biome before prettier, ruff format before
+// black, goimports before gofmt -- and Format runs the first one whose
+// binary is on PATH (and, if formatting offline, doesn't require network).
+type Candidate struct {
+	Name         string        `yaml:"name"`
+	Command      []string      `yaml:"command"`
+	Timeout      time.Duration `yaml:"timeout"`
+	AllowNetwork bool          `yaml:"allow_network"`
+}
+
+// Rule maps a set of glob patterns, matched against a file's base name, to
+// its fallback chain of Candidates.
+type Rule struct {
+	Globs      []string    `yaml:"globs"`
+	Candidates []Candidate `yaml:"candidates"`
+}
+
+// Registry is the full formatters.yaml document.
+type Registry struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// ConfigPath returns ~/.storyblok-cli/formatters.yaml.
+func ConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("determine home dir: %w", err)
+	}
+	return filepath.Join(home, ".storyblok-cli", "formatters.yaml"), nil
+}
+
+// Load reads the registry from ~/.storyblok-cli/formatters.yaml, falling
+// back to DefaultRegistry when that file doesn't exist or declares no
+// rules.
+func Load() (*Registry, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return DefaultRegistry(), nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultRegistry(), nil
+		}
+		return nil, fmt.Errorf("read formatters config %q: %w", path, err)
+	}
+	var reg Registry
+	if err := yaml.Unmarshal(b, &reg); err != nil {
+		return nil, fmt.Errorf("parse formatters config %q: %w", path, err)
+	}
+	if len(reg.Rules) == 0 {
+		return DefaultRegistry(), nil
+	}
+	return &reg, nil
+}
+
+// DefaultRegistry mirrors the fallback chains this package replaces:
+// biome before prettier for JS/TS/JSON/CSS/HTML/MD, ruff format before
+// black for Python, goimports before gofmt for Go.
+func DefaultRegistry() *Registry {
+	return &Registry{Rules: []Rule{
+		{
+			Globs: []string{"*.ts", "*.tsx", "*.js", "*.jsx", "*.json", "*.css", "*.html", "*.md"},
+			Candidates: []Candidate{
+				{Name: "biome", Command: []string{"npx", "--yes", "@biomejs/biome", "format", "--stdin-file-path", "{{file}}"}, AllowNetwork: true},
+				{Name: "prettier", Command: []string{"npx", "--yes", "prettier", "--stdin-filepath", "{{file}}"}, AllowNetwork: true},
+			},
+		},
+		{
+			Globs: []string{"*.py"},
+			Candidates: []Candidate{
+				{Name: "ruff", Command: []string{"ruff", "format", "-"}},
+				{Name: "black", Command: []string{"black", "-", "-q"}},
+			},
+		},
+		{
+			Globs: []string{"*.go"},
+			Candidates: []Candidate{
+				{Name: "goimports", Command: []string{"goimports"}},
+				{Name: "gofmt", Command: []string{"gofmt"}},
+			},
+		},
+	}}
+}
+
+// ruleFor returns the first Rule whose globs match path's base name.
+func (r *Registry) ruleFor(path string) (Rule, bool) {
+	base := filepath.Base(path)
+	for _, rule := range r.Rules {
+		for _, glob := range rule.Globs {
+			if ok, _ := filepath.Match(glob, base); ok {
+				return rule, true
+			}
+		}
+	}
+	return Rule{}, false
+}
+
+// Format runs path's rule chain against content, in priority order,
+// returning the first candidate's output whose binary is on PATH (and,
+// when offline, doesn't require network) and that exits cleanly. If no
+// rule matches path, Format returns content unchanged with no warning. If
+// every candidate is unavailable/excluded/fails, Format returns content
+// unchanged plus a human-readable warning -- a
+// missing or broken formatter is never fatal to the scaffold, matching the
+// prior silent-no-op behavior except that the reason is now surfaced.
+func Format(ctx context.Context, reg *Registry, path, content string, offline bool) (formatted string, warning string) {
+	rule, ok := reg.ruleFor(path)
+	if !ok {
+		return content, ""
+	}
+
+	var tried []string
+	var lastErr error
+	for _, c := range rule.Candidates {
+		if offline && c.AllowNetwork {
+			continue
+		}
+		if len(c.Command) == 0 {
+			continue
+		}
+		if _, err := exec.LookPath(c.Command[0]); err != nil {
+			continue
+		}
+		tried = append(tried, c.Name)
+		out, err := runCandidate(ctx, c, path, content)
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", c.Name, err)
+			continue
+		}
+		return out, ""
+	}
+
+	switch {
+	case lastErr != nil:
+		return content, fmt.Sprintf("formatting %s failed (tried %s): %v", path, strings.Join(tried, ", "), lastErr)
+	case len(tried) == 0:
+		return content, fmt.Sprintf("no formatter available for %s (rule candidates: %s)", path, candidateNames(rule.Candidates))
+	default:
+		return content, ""
+	}
+}
+
+func candidateNames(cs []Candidate) string {
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// runCandidate runs one formatter command, piping content in on stdin and
+// returning what it writes to stdout, bounded by c.Timeout (defaultTimeout
+// if unset).
+func runCandidate(ctx context.Context, c Candidate, path, content string) (string, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	cctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := make([]string, len(c.Command)-1)
+	for i, a := range c.Command[1:] {
+		args[i] = strings.ReplaceAll(a, "{{file}}", path)
+	}
+
+	cmd := exec.CommandContext(cctx, c.Command[0], args...)
+	cmd.Stdin = strings.NewReader(content)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if cctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("timed out after %s", timeout)
+		}
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
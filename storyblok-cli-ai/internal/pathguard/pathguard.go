@@ -0,0 +1,87 @@
+// Package pathguard centralizes the path-safety check every write site that
+// trusts a backend-supplied relative path -- file_start/file_chunk's temp
+// append, the overlay writer's changed_files paths, and the package.json
+// rewrite -- must run before touching disk. A malicious or buggy backend
+// can send an absolute path or one laced with ".." segments; SanitizeRelPath
+// rejects both, plus writes into denied directories like .git or
+// node_modules.
+package pathguard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultDenyGlobs blocks writes into VCS metadata and dependency trees --
+// neither is ever legitimately part of a generated scaffold or overlay.
+var DefaultDenyGlobs = []string{".git", "node_modules"}
+
+// SanitizeRelPath validates that p is relative and, once cleaned and joined
+// onto base, still resolves inside base and clears denyGlobs (matched
+// against each path segment), then returns the resulting absolute path. A
+// nil denyGlobs skips that check.
+func SanitizeRelPath(base, p string, denyGlobs []string) (string, error) {
+	if strings.TrimSpace(p) == "" {
+		return "", fmt.Errorf("path is empty")
+	}
+	if filepath.IsAbs(p) || strings.HasPrefix(filepath.FromSlash(p), string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q must be relative", p)
+	}
+
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", fmt.Errorf("resolve base dir %q: %w", base, err)
+	}
+	target := filepath.Join(absBase, filepath.Clean(filepath.FromSlash(p)))
+	rel, err := filepath.Rel(absBase, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes its base directory", p)
+	}
+
+	for _, seg := range strings.Split(filepath.ToSlash(rel), "/") {
+		for _, glob := range denyGlobs {
+			if ok, _ := filepath.Match(glob, seg); ok {
+				return "", fmt.Errorf("path %q is denied (matches %q)", p, glob)
+			}
+		}
+	}
+
+	if err := denySymlinkEscape(absBase, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// denySymlinkEscape resolves target's deepest *existing* ancestor
+// directory and confirms it's still inside base once symlinks are
+// followed. Everything below that ancestor doesn't exist yet, so a stream
+// can't have planted a symlink there to escape through. This is a
+// portable, no-build-tag stand-in for opening the parent with O_NOFOLLOW,
+// which Go's os package doesn't expose uniformly across the platforms
+// disk.Disk targets (ftp/sftp/s3 have no such notion at all).
+func denySymlinkEscape(base, target string) error {
+	resolvedBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return nil // base doesn't exist yet; nothing to escape through
+	}
+	for dir := filepath.Dir(target); ; {
+		if _, statErr := os.Lstat(dir); statErr == nil {
+			resolved, evalErr := filepath.EvalSymlinks(dir)
+			if evalErr != nil {
+				return fmt.Errorf("resolve %q: %w", dir, evalErr)
+			}
+			rel, relErr := filepath.Rel(resolvedBase, resolved)
+			if relErr != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("path %q escapes its base directory via a symlink", target)
+			}
+			return nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
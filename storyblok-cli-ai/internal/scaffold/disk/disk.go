@@ -0,0 +1,113 @@
+// Package disk abstracts the destination a scaffolded project is written to,
+// so callers can target the local filesystem, a tar stream, or a remote
+// backend (FTP, SFTP, S3) via a single URL passed to --output.
+package disk
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// EntryType distinguishes files from directories when walking a Disk.
+type EntryType int
+
+const (
+	EntryTypeFile EntryType = iota
+	EntryTypeFolder
+)
+
+// Entry describes a single item returned by WalkDir.
+type Entry struct {
+	Path string
+	Type EntryType
+}
+
+func (e Entry) IsDir() bool { return e.Type == EntryTypeFolder }
+
+// WalkFunc is invoked once per entry found by WalkDir. Returning an error
+// aborts the walk.
+type WalkFunc func(path string, entry Entry) error
+
+// Disk is the destination abstraction every scaffold write goes through.
+// Paths are always slash-separated and relative to the Disk's root.
+type Disk interface {
+	// Exists reports whether path exists under the Disk's root.
+	Exists(path string) (bool, error)
+	// MkdirAll creates path and any missing parents.
+	MkdirAll(path string) error
+	// Write creates or truncates path and copies r into it.
+	Write(path string, r io.Reader) error
+	// Rename moves oldPath to newPath, atomically if the backend supports it.
+	Rename(oldPath, newPath string) error
+	// RemoveAll removes path and everything under it.
+	RemoveAll(path string) error
+	// WalkDir walks the tree rooted at path, invoking fn for each entry.
+	WalkDir(path string, fn WalkFunc) error
+	// Close releases any underlying connection (network backends).
+	Close() error
+}
+
+// Reader is implemented by Disk backends that can read a file back, used by
+// diff-preview and checksum-verification features that need to compare
+// generated content against what's already on disk.
+type Reader interface {
+	Open(path string) (io.ReadCloser, error)
+}
+
+// AtomicMover is implemented by Disk backends that can emulate an atomic
+// directory move with a fallback for backends (or filesystems) that reject
+// a direct rename. Move everything into a staging path first, then call
+// MoveAtomic to publish it under the final name.
+type AtomicMover interface {
+	MoveAtomic(staging, target string) error
+}
+
+// MoveAtomic publishes staging under target, using the Disk's own
+// AtomicMover implementation when available and falling back to a plain
+// Rename otherwise.
+func MoveAtomic(d Disk, staging, target string) error {
+	if m, ok := d.(AtomicMover); ok {
+		return m.MoveAtomic(staging, target)
+	}
+	return d.Rename(staging, target)
+}
+
+// New dispatches on the URL scheme of rawURL and returns a Disk rooted at
+// the path component. A bare path with no scheme (or "file://") resolves to
+// the local filesystem; "tar:-" or "tar:/path/to/out.tar" streams a tarball
+// instead (see tarDisk); "ftp://", "sftp://" and "s3://" dial the matching
+// remote backend.
+func New(rawURL string) (Disk, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("disk: empty destination URL")
+	}
+	// tar:<dest> is a one-off shorthand rather than a real URL scheme (so
+	// "tar:-" for stdout doesn't collide with the "://" check below), per
+	// buildkit's `--output type=tar,dest=-` convention.
+	if strings.HasPrefix(rawURL, "tar:") {
+		return newTarDisk(strings.TrimPrefix(rawURL, "tar:"))
+	}
+	if !strings.Contains(rawURL, "://") {
+		return newLocalDisk(rawURL), nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("disk: parse destination URL %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return newLocalDisk(u.Path), nil
+	case "ftp":
+		return newFTPDisk(u)
+	case "sftp":
+		return newSFTPDisk(u)
+	case "s3":
+		return newS3Disk(u)
+	default:
+		return nil, fmt.Errorf("disk: unsupported scheme %q in %q", u.Scheme, rawURL)
+	}
+}
@@ -0,0 +1,239 @@
+package disk
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// ftpDisk wraps a single FTP control connection. The underlying protocol is
+// strictly request/response, so every operation is serialized with mu —
+// two goroutines issuing commands on the same connection at once corrupts
+// the control channel.
+type ftpDisk struct {
+	mu   sync.Mutex
+	conn *ftp.ServerConn
+	root string
+}
+
+func newFTPDisk(u *url.URL) (*ftpDisk, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = addr + ":21"
+	}
+	conn, err := ftp.Dial(addr, ftp.DialWithTimeout(30*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("disk: ftp dial %s: %w", addr, err)
+	}
+	user := "anonymous"
+	pass := "anonymous"
+	if u.User != nil {
+		user = u.User.Username()
+		if p, ok := u.User.Password(); ok {
+			pass = p
+		}
+	}
+	if err := conn.Login(user, pass); err != nil {
+		_ = conn.Quit()
+		return nil, fmt.Errorf("disk: ftp login: %w", err)
+	}
+	return &ftpDisk{conn: conn, root: strings.TrimSuffix(u.Path, "/")}, nil
+}
+
+func (d *ftpDisk) abs(p string) string {
+	return path.Join(d.root, p)
+}
+
+func (d *ftpDisk) Exists(p string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries, err := d.conn.List(path.Dir(d.abs(p)))
+	if err != nil {
+		return false, nil
+	}
+	base := path.Base(d.abs(p))
+	for _, e := range entries {
+		if e.Name == base {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *ftpDisk) MkdirAll(p string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mkdirAllLocked(d.abs(p))
+}
+
+// mkdirAllLocked walks from the root creating each path segment; FTP has no
+// native mkdir -p, so missing-dir errors on MakeDir are treated as progress.
+func (d *ftpDisk) mkdirAllLocked(full string) error {
+	full = strings.Trim(full, "/")
+	if full == "" {
+		return nil
+	}
+	segments := strings.Split(full, "/")
+	cur := ""
+	for _, seg := range segments {
+		cur = cur + "/" + seg
+		_ = d.conn.MakeDir(cur) // ignore "already exists" style errors
+	}
+	return nil
+}
+
+func (d *ftpDisk) Write(p string, r io.Reader) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	full := d.abs(p)
+	if err := d.mkdirAllLocked(path.Dir(full)); err != nil {
+		return err
+	}
+	return d.conn.Stor(full, r)
+}
+
+func (d *ftpDisk) Rename(oldPath, newPath string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conn.Rename(d.abs(oldPath), d.abs(newPath))
+}
+
+func (d *ftpDisk) RemoveAll(p string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.removeAllLocked(d.abs(p))
+}
+
+func (d *ftpDisk) removeAllLocked(full string) error {
+	entries, err := d.conn.List(full)
+	if err != nil {
+		// assume it's a file
+		return d.conn.Delete(full)
+	}
+	for _, e := range entries {
+		if e.Name == "." || e.Name == ".." {
+			continue
+		}
+		child := full + "/" + e.Name
+		if e.Type == ftp.EntryTypeFolder {
+			if err := d.removeAllLocked(child); err != nil {
+				return err
+			}
+		} else {
+			if err := d.conn.Delete(child); err != nil {
+				return err
+			}
+		}
+	}
+	return d.conn.RemoveDirRecur(full)
+}
+
+func (d *ftpDisk) WalkDir(p string, fn WalkFunc) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.walkLocked(d.abs(p), p, fn)
+}
+
+func (d *ftpDisk) walkLocked(full, rel string, fn WalkFunc) error {
+	entries, err := d.conn.List(full)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name == "." || e.Name == ".." {
+			continue
+		}
+		childRel := path.Join(rel, e.Name)
+		childFull := path.Join(full, e.Name)
+		entryType := EntryTypeFile
+		if e.Type == ftp.EntryTypeFolder {
+			entryType = EntryTypeFolder
+		}
+		if err := fn(childRel, Entry{Path: childRel, Type: entryType}); err != nil {
+			return err
+		}
+		if entryType == EntryTypeFolder {
+			if err := d.walkLocked(childFull, childRel, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *ftpDisk) Open(p string) (io.ReadCloser, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conn.Retr(d.abs(p))
+}
+
+// MoveAtomic publishes the already-uploaded staging directory as target
+// with a single Rename, falling back to recursive retrieve+store+delete
+// when the server rejects a cross-directory rename.
+func (d *ftpDisk) MoveAtomic(staging, target string) error {
+	return atomicMoveFTP(d, staging, target)
+}
+
+func (d *ftpDisk) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conn.Quit()
+}
+
+// atomicMoveFTP issues a single Rename of an already-uploaded staging
+// directory to finalDir, mirroring the local atomic-rename-with-fallback
+// behavior. When the server rejects a cross-directory rename, it falls back
+// to a recursive retrieve+store+delete.
+func atomicMoveFTP(d *ftpDisk, stagingDir, finalDir string) error {
+	if err := d.Rename(stagingDir, finalDir); err == nil {
+		return nil
+	}
+
+	var entries []Entry
+	if err := d.WalkDir(stagingDir, func(_ string, entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("disk: ftp list staging dir for fallback copy: %w", err)
+	}
+
+	for _, e := range entries {
+		destRel := finalDir + strings.TrimPrefix(e.Path, stagingDir)
+		if e.IsDir() {
+			if err := d.MkdirAll(destRel); err != nil {
+				return err
+			}
+			continue
+		}
+		d.mu.Lock()
+		r, err := d.conn.Retr(d.abs(e.Path))
+		if err != nil {
+			d.mu.Unlock()
+			return fmt.Errorf("disk: ftp retrieve %s: %w", e.Path, err)
+		}
+		data, readErr := io.ReadAll(r)
+		r.Close()
+		d.mu.Unlock()
+		if readErr != nil {
+			return fmt.Errorf("disk: ftp read %s: %w", e.Path, readErr)
+		}
+		if err := d.Write(destRel, strings.NewReader(string(data))); err != nil {
+			return err
+		}
+	}
+
+	return d.RemoveAll(stagingDir)
+}
+
+// stagingName returns a unique ".tmp-<name>-<rand>" directory name for
+// staging an atomic FTP upload.
+func stagingName(name string) string {
+	return fmt.Sprintf(".tmp-%s-%06d", name, rand.Intn(1_000_000))
+}
@@ -0,0 +1,131 @@
+package disk
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// localDisk implements Disk against the OS filesystem, rooted at dir.
+type localDisk struct {
+	root string
+}
+
+func newLocalDisk(dir string) *localDisk {
+	return &localDisk{root: dir}
+}
+
+func (d *localDisk) abs(path string) string {
+	return filepath.Join(d.root, filepath.FromSlash(path))
+}
+
+func (d *localDisk) Exists(path string) (bool, error) {
+	_, err := os.Stat(d.abs(path))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (d *localDisk) MkdirAll(path string) error {
+	return os.MkdirAll(d.abs(path), 0o755)
+}
+
+func (d *localDisk) Write(path string, r io.Reader) error {
+	full := d.abs(path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(full)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (d *localDisk) Rename(oldPath, newPath string) error {
+	return os.Rename(d.abs(oldPath), d.abs(newPath))
+}
+
+func (d *localDisk) RemoveAll(path string) error {
+	return os.RemoveAll(d.abs(path))
+}
+
+func (d *localDisk) WalkDir(path string, fn WalkFunc) error {
+	root := d.abs(path)
+	return filepath.WalkDir(root, func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, rerr := filepath.Rel(root, p)
+		if rerr != nil {
+			return rerr
+		}
+		rel = filepath.ToSlash(rel)
+		entryType := EntryTypeFile
+		if de.IsDir() {
+			entryType = EntryTypeFolder
+		}
+		return fn(rel, Entry{Path: rel, Type: entryType})
+	})
+}
+
+func (d *localDisk) Open(path string) (io.ReadCloser, error) {
+	return os.Open(d.abs(path))
+}
+
+func (d *localDisk) Close() error { return nil }
+
+// MoveAtomic renames staging to target, falling back to a recursive copy
+// when the two paths live on different filesystems (cross-device rename).
+func (d *localDisk) MoveAtomic(staging, target string) error {
+	stagingFull, targetFull := d.abs(staging), d.abs(target)
+	if err := os.Rename(stagingFull, targetFull); err == nil {
+		return nil
+	}
+	if err := copyDirLocal(stagingFull, targetFull); err != nil {
+		return fmt.Errorf("disk: local copy fallback %s -> %s: %w", stagingFull, targetFull, err)
+	}
+	return os.RemoveAll(stagingFull)
+}
+
+func copyDirLocal(src, dst string) error {
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return err
+	}
+	return filepath.WalkDir(src, func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, rerr := filepath.Rel(src, p)
+		if rerr != nil {
+			return rerr
+		}
+		target := filepath.Join(dst, rel)
+		if de.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		in, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		_, err = io.Copy(out, in)
+		return err
+	})
+}
@@ -0,0 +1,155 @@
+package disk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3Disk treats an S3 bucket+prefix as a Disk. S3 has no real directories or
+// rename, so MkdirAll is a no-op and Rename is copy+delete of every object
+// under the prefix.
+type s3Disk struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func newS3Disk(u *url.URL) (*s3Disk, error) {
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("disk: load AWS config: %w", err)
+	}
+	return &s3Disk{
+		client: s3.NewFromConfig(cfg),
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (d *s3Disk) key(p string) string {
+	if d.prefix == "" {
+		return p
+	}
+	return d.prefix + "/" + strings.TrimPrefix(p, "/")
+}
+
+func (d *s3Disk) Exists(p string) (bool, error) {
+	_, err := d.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(d.key(p)),
+	})
+	if err == nil {
+		return true, nil
+	}
+	return false, nil
+}
+
+// MkdirAll is a no-op: S3 keys are flat and directories are implied by '/'.
+func (d *s3Disk) MkdirAll(p string) error { return nil }
+
+func (d *s3Disk) Write(p string, r io.Reader) error {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = d.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(d.key(p)),
+		Body:   bytes.NewReader(buf),
+	})
+	return err
+}
+
+func (d *s3Disk) Open(p string) (io.ReadCloser, error) {
+	out, err := d.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(d.key(p)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (d *s3Disk) Rename(oldPath, newPath string) error {
+	var toDelete []string
+	if err := d.WalkDir(oldPath, func(relPath string, entry Entry) error {
+		if entry.IsDir() {
+			return nil
+		}
+		src := d.key(relPath)
+		dst := d.key(newPath + strings.TrimPrefix(relPath, oldPath))
+		if _, err := d.client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:     aws.String(d.bucket),
+			CopySource: aws.String(d.bucket + "/" + src),
+			Key:        aws.String(dst),
+		}); err != nil {
+			return fmt.Errorf("disk: s3 copy %s -> %s: %w", src, dst, err)
+		}
+		toDelete = append(toDelete, src)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, key := range toDelete {
+		if _, err := d.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+			Bucket: aws.String(d.bucket),
+			Key:    aws.String(key),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *s3Disk) RemoveAll(p string) error {
+	var toDelete []string
+	if err := d.WalkDir(p, func(relPath string, entry Entry) error {
+		if !entry.IsDir() {
+			toDelete = append(toDelete, d.key(relPath))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, key := range toDelete {
+		if _, err := d.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+			Bucket: aws.String(d.bucket),
+			Key:    aws.String(key),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *s3Disk) WalkDir(p string, fn WalkFunc) error {
+	prefix := d.key(p)
+	paginator := s3.NewListObjectsV2Paginator(d.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(d.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return fmt.Errorf("disk: s3 list %s: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			rel := strings.TrimPrefix(aws.ToString(obj.Key), d.prefix+"/")
+			if err := fn(rel, Entry{Path: rel, Type: EntryTypeFile}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *s3Disk) Close() error { return nil }
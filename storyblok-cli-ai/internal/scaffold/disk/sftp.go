@@ -0,0 +1,137 @@
+package disk
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpDisk wraps an SFTP session rooted at u.Path.
+type sftpDisk struct {
+	client *sftp.Client
+	sshc   *ssh.Client
+	root   string
+}
+
+func newSFTPDisk(u *url.URL) (*sftpDisk, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = addr + ":22"
+	}
+	user := "anonymous"
+	var authMethods []ssh.AuthMethod
+	if u.User != nil {
+		user = u.User.Username()
+		if pass, ok := u.User.Password(); ok {
+			authMethods = append(authMethods, ssh.Password(pass))
+		}
+	}
+
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            authMethods,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // TODO: support known_hosts verification
+	}
+	sshc, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, fmt.Errorf("disk: sftp dial %s: %w", addr, err)
+	}
+	client, err := sftp.NewClient(sshc)
+	if err != nil {
+		_ = sshc.Close()
+		return nil, fmt.Errorf("disk: sftp new client: %w", err)
+	}
+
+	return &sftpDisk{client: client, sshc: sshc, root: strings.TrimSuffix(u.Path, "/")}, nil
+}
+
+func (d *sftpDisk) abs(p string) string {
+	return path.Join(d.root, p)
+}
+
+func (d *sftpDisk) Exists(p string) (bool, error) {
+	_, err := d.client.Stat(d.abs(p))
+	if err == nil {
+		return true, nil
+	}
+	if isNotExistSFTP(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (d *sftpDisk) MkdirAll(p string) error {
+	return d.client.MkdirAll(d.abs(p))
+}
+
+func (d *sftpDisk) Write(p string, r io.Reader) error {
+	full := d.abs(p)
+	if err := d.client.MkdirAll(path.Dir(full)); err != nil {
+		return err
+	}
+	f, err := d.client.Create(full)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (d *sftpDisk) Rename(oldPath, newPath string) error {
+	return d.client.PosixRename(d.abs(oldPath), d.abs(newPath))
+}
+
+func (d *sftpDisk) RemoveAll(p string) error {
+	return d.client.RemoveAll(d.abs(p))
+}
+
+func (d *sftpDisk) WalkDir(p string, fn WalkFunc) error {
+	root := d.abs(p)
+	walker := d.client.Walk(root)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(walker.Path(), root)
+		rel = strings.TrimPrefix(rel, "/")
+		if rel == "" {
+			continue
+		}
+		entryType := EntryTypeFile
+		if walker.Stat().IsDir() {
+			entryType = EntryTypeFolder
+		}
+		if err := fn(rel, Entry{Path: rel, Type: entryType}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *sftpDisk) Open(p string) (io.ReadCloser, error) {
+	return d.client.Open(d.abs(p))
+}
+
+func (d *sftpDisk) Close() error {
+	cerr := d.client.Close()
+	serr := d.sshc.Close()
+	if cerr != nil {
+		return cerr
+	}
+	return serr
+}
+
+// isNotExistSFTP avoids importing "os" solely for the sentinel check, since
+// sftp.Client.Stat returns *sftp.StatusError wrapping SSH_FX_NO_SUCH_FILE.
+func isNotExistSFTP(err error) bool {
+	if se, ok := err.(*sftp.StatusError); ok {
+		return se.Code == uint32(sftp.ErrSSHFxNoSuchFile)
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package disk
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// tarDisk streams every write into a tar archive instead of a filesystem,
+// e.g. for piping a generated scaffold into a Docker build context or a CI
+// artifact. It's write-only and sequential (mirroring buildkit's type=tar
+// exporter): Exists/Rename/RemoveAll/WalkDir/Open all fail, since there's no
+// tree to query or read back mid-stream. Write buffers its reader fully
+// before emitting a tar header, since tar headers need the entry size
+// up front.
+type tarDisk struct {
+	mu          sync.Mutex
+	tw          *tar.Writer
+	closer      io.Closer
+	dirsWritten map[string]bool
+}
+
+// newTarDisk opens dest for writing a tar stream: "-" (or "") streams to
+// stdout, anything else is created as a regular file.
+func newTarDisk(dest string) (*tarDisk, error) {
+	var w io.Writer
+	var closer io.Closer
+	if dest == "" || dest == "-" {
+		w = os.Stdout
+	} else {
+		f, err := os.Create(dest)
+		if err != nil {
+			return nil, fmt.Errorf("disk: create tar output %q: %w", dest, err)
+		}
+		w, closer = f, f
+	}
+	return &tarDisk{tw: tar.NewWriter(w), closer: closer, dirsWritten: map[string]bool{}}, nil
+}
+
+func (d *tarDisk) Write(p string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("disk: buffer %q for tar: %w", p, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.ensureParentDirsLocked(path.Dir(p)); err != nil {
+		return err
+	}
+	if err := d.tw.WriteHeader(&tar.Header{Name: p, Mode: 0o644, Size: int64(len(data))}); err != nil {
+		return fmt.Errorf("disk: tar header %q: %w", p, err)
+	}
+	_, err = d.tw.Write(data)
+	return err
+}
+
+func (d *tarDisk) MkdirAll(p string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ensureParentDirsLocked(p)
+}
+
+// ensureParentDirsLocked writes a tar.TypeDir header for p and every
+// ancestor not already written, so directories survive even when they end
+// up holding no files. Must be called with d.mu held.
+func (d *tarDisk) ensureParentDirsLocked(p string) error {
+	p = strings.Trim(path.Clean(p), "/")
+	if p == "" || p == "." || d.dirsWritten[p] {
+		return nil
+	}
+	if parent := path.Dir(p); parent != "." && parent != "/" {
+		if err := d.ensureParentDirsLocked(parent); err != nil {
+			return err
+		}
+	}
+	if err := d.tw.WriteHeader(&tar.Header{Name: p + "/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		return fmt.Errorf("disk: tar dir header %q: %w", p, err)
+	}
+	d.dirsWritten[p] = true
+	return nil
+}
+
+func (d *tarDisk) Exists(p string) (bool, error) {
+	return false, fmt.Errorf("disk: tar backend is write-only; Exists(%q) not supported", p)
+}
+
+func (d *tarDisk) Rename(oldPath, newPath string) error {
+	return fmt.Errorf("disk: tar backend is write-only; Rename not supported")
+}
+
+func (d *tarDisk) RemoveAll(p string) error {
+	return fmt.Errorf("disk: tar backend is write-only; RemoveAll(%q) not supported", p)
+}
+
+func (d *tarDisk) WalkDir(p string, fn WalkFunc) error {
+	return fmt.Errorf("disk: tar backend is write-only; WalkDir not supported")
+}
+
+func (d *tarDisk) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.tw.Close(); err != nil {
+		return fmt.Errorf("disk: close tar stream: %w", err)
+	}
+	if d.closer != nil {
+		return d.closer.Close()
+	}
+	return nil
+}
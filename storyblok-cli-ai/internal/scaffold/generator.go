@@ -1,18 +1,39 @@
 package scaffold
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
+
+	cerrors "storyblok-cli-ai/internal/errors"
+	"storyblok-cli-ai/internal/scaffold/disk"
+	"storyblok-cli-ai/pkg/progress"
+)
+
+// Mode controls how GenerateAndWriteProject behaves when projectDir already
+// exists.
+type Mode string
+
+const (
+	// ModeCreate fails if projectDir already exists (the original behavior).
+	ModeCreate Mode = "create"
+	// ModeMerge writes only the files that don't already exist on disk.
+	ModeMerge Mode = "merge"
+	// ModeOverwrite replaces every file, refusing files the user has
+	// locally modified since the last scaffold unless Force is set.
+	ModeOverwrite Mode = "overwrite"
+	// ModeDiff writes nothing; it prints a unified diff of each generated
+	// file against what's on disk.
+	ModeDiff Mode = "diff"
 )
 
 // GenerateRequest and response shapes (match the backend)
@@ -20,6 +41,29 @@ type GenerateRequest struct {
 	UserAnswers     map[string]interface{} `json:"user_answers"`
 	StoryblokSchema map[string]interface{} `json:"storyblok_schema"`
 	Options         map[string]interface{} `json:"options,omitempty"`
+
+	// Mode and Force are local write-side concerns (not sent to the
+	// backend); they are read by GenerateAndWriteProject to decide how to
+	// reconcile generated files with an already-scaffolded projectDir.
+	Mode  Mode `json:"-"`
+	Force bool `json:"-"`
+
+	// Reporter receives progress/warning events when the backend streams
+	// NDJSON. Defaults to progress.NewStderrReporter() when nil.
+	Reporter progress.Reporter `json:"-"`
+}
+
+// streamEvent is one line of the NDJSON protocol served by backends that
+// support "Accept: application/x-ndjson".
+type streamEvent struct {
+	Type        string `json:"type"`
+	Path        string `json:"path,omitempty"`
+	Content     string `json:"content,omitempty"`
+	Done        int    `json:"done,omitempty"`
+	Total       int    `json:"total,omitempty"`
+	Message     string `json:"message,omitempty"`
+	Text        string `json:"text,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
 }
 
 type FileOut struct {
@@ -35,9 +79,41 @@ type GenerateResponse struct {
 
 // GenerateAndWriteProject posts payload to backend and atomically writes files into projectDir.
 // backendURL must be the full endpoint, e.g., http://127.0.0.1:8000/generate/
+//
+// It prefers the backend's streaming NDJSON protocol (one typed event per
+// line: file/progress/warning/done) so files land on disk as they're
+// generated instead of only after the full response buffers in memory.
+// Backends that don't support it fall back to the original single
+// application/json response. Ctrl-C cancels the request context, which
+// aborts the stream and removes anything already staged.
 func GenerateAndWriteProject(backendURL string, payload GenerateRequest, projectDir string) error {
-	// 1) POST the request
-	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	reporter := payload.Reporter
+	if reporter == nil {
+		reporter = progress.NewStderrReporter()
+	}
+
+	d, targetPath, err := resolveDisk(projectDir)
+	if err != nil {
+		return fmt.Errorf("resolve destination: %w", err)
+	}
+	defer d.Close()
+
+	mode := payload.Mode
+	if mode == "" {
+		mode = ModeCreate
+	}
+
+	targetExists, err := d.Exists(targetPath)
+	if err != nil {
+		return fmt.Errorf("check destination: %w", err)
+	}
+	if targetExists && mode == ModeCreate {
+		return cerrors.WithStackIf(fmt.Errorf("%w: %s (remove or choose another name, or use merge/overwrite/diff mode)", cerrors.ErrTargetExists, projectDir))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, 300*time.Second)
 	defer cancel()
 
 	reqBody, err := json.Marshal(payload)
@@ -50,65 +126,75 @@ func GenerateAndWriteProject(backendURL string, payload GenerateRequest, project
 		return fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/x-ndjson, application/json")
 
 	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("call backend: %w", err)
+		return cerrors.WithStackIf(fmt.Errorf("%w: %v", cerrors.ErrBackendUnavailable, err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("backend returned status %d: %s", resp.StatusCode, string(body))
+		return cerrors.WithStackIf(&cerrors.ErrBackendStatus{Code: resp.StatusCode, Body: string(body)})
 	}
 
-	var genResp GenerateResponse
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&genResp); err != nil {
-		return fmt.Errorf("decode backend response: %w", err)
+	// If we're scaffolding fresh, stage straight away so NDJSON file events
+	// can be written to disk as they arrive rather than held in memory.
+	var staging string
+	if !targetExists {
+		staging = stagingPath(targetPath)
+		if err := d.MkdirAll(staging); err != nil {
+			return fmt.Errorf("create staging dir: %w", err)
+		}
+		defer func() {
+			if ok, _ := d.Exists(targetPath); !ok {
+				_ = d.RemoveAll(staging)
+			}
+		}()
 	}
 
-	if len(genResp.Files) == 0 {
-		return errors.New("backend returned no files")
+	var genResp GenerateResponse
+	if strings.Contains(resp.Header.Get("Content-Type"), "ndjson") {
+		genResp, err = streamGenerateResponse(ctx, resp.Body, d, staging, reporter)
+	} else {
+		dec := json.NewDecoder(resp.Body)
+		err = dec.Decode(&genResp)
+		if err == nil && staging != "" {
+			for _, f := range genResp.Files {
+				if werr := writeFileToDisk(d, staging, f.Path, f.Content); werr != nil {
+					err = fmt.Errorf("write file %q: %w", f.Path, werr)
+					break
+				}
+			}
+		}
 	}
-
-	// 2) Validate projectDir & tmp dir
-	absTarget, err := filepath.Abs(projectDir)
 	if err != nil {
-		return fmt.Errorf("determine abs path: %w", err)
-	}
-	if exists(absTarget) {
-		return fmt.Errorf("target directory already exists: %s (remove or choose another name)", absTarget)
+		if ctx.Err() != nil {
+			return fmt.Errorf("generation canceled: %w", ctx.Err())
+		}
+		return fmt.Errorf("read backend response: %w", err)
 	}
 
-	parent := filepath.Dir(absTarget)
-	tmp, err := os.MkdirTemp(parent, ".tmp-"+filepath.Base(absTarget)+"-")
-	if err != nil {
-		return fmt.Errorf("create temp dir: %w", err)
-	}
-	// cleanup tmp on error
-	cleanup := func() {
-		_ = os.RemoveAll(tmp)
+	if len(genResp.Files) == 0 {
+		return cerrors.WithStackIf(cerrors.New("backend returned no files"))
 	}
-	defer func() {
-		if !exists(absTarget) {
-			cleanup()
-		}
-	}()
 
-	// 3) Write files to tmp
-	for _, f := range genResp.Files {
-		if err := writeFileToDir(tmp, f.Path, f.Content); err != nil {
-			return fmt.Errorf("write file %q: %w", f.Path, err)
-		}
+	if targetExists {
+		// Re-scaffolding an existing project: reconcile file-by-file
+		// according to mode instead of an all-or-nothing directory move.
+		return reconcileScaffold(d, targetPath, genResp.Files, mode, payload.Force)
 	}
 
-	// 4) Move tmp -> target (atomic when possible)
-	if err := moveDirAtomic(tmp, absTarget); err != nil {
+	if err := disk.MoveAtomic(d, staging, targetPath); err != nil {
 		return fmt.Errorf("move project into place: %w", err)
 	}
 
-	fmt.Println("Project scaffolded at:", absTarget)
+	if err := writeManifest(d, targetPath, genResp.Files); err != nil {
+		fmt.Println("warning: failed to write scaffold manifest:", err)
+	}
+
+	fmt.Println("Project scaffolded at:", projectDir)
 
 	// Print warnings from metadata if any
 	if genResp.Metadata != nil {
@@ -125,146 +211,141 @@ func GenerateAndWriteProject(backendURL string, payload GenerateRequest, project
 	return nil
 }
 
-func exists(path string) bool {
-	_, err := os.Stat(path)
-	return err == nil
-}
-
-func writeFileToDir(root string, relPath string, content string) error {
-	// sanitize: disallow absolute and ".." traversal
-	if filepath.IsAbs(relPath) {
-		return fmt.Errorf("absolute path not allowed: %s", relPath)
-	}
-	clean := filepath.Clean(relPath)
-	// Prevent path traversal that escapes root
-	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) || strings.Contains(clean, ".."+string(filepath.Separator)) {
-		return fmt.Errorf("path traversal not allowed: %s", relPath)
-	}
-
-	fullPath := filepath.Join(root, clean)
-	dir := filepath.Dir(fullPath)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("mkdirall %s: %w", dir, err)
-	}
+// streamGenerateResponse reads the NDJSON event stream, writing each "file"
+// event's content into staging as it arrives (when staging is non-empty —
+// i.e. this is a fresh scaffold, not a re-scaffold reconcile) and reporting
+// "progress"/"warning" events through reporter. It stops at the first
+// "done" event or when ctx is canceled.
+func streamGenerateResponse(ctx context.Context, body io.Reader, d disk.Disk, staging string, reporter progress.Reporter) (GenerateResponse, error) {
+	var out GenerateResponse
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
 
-	// atomic-ish write: write to tmp file and rename
-	tmpFile := fullPath + ".tmp"
-	if err := os.WriteFile(tmpFile, []byte(content), 0o644); err != nil {
-		return fmt.Errorf("write tmp file: %w", err)
-	}
-	if err := os.Rename(tmpFile, fullPath); err != nil {
-		// fallback to copyFile if rename fails
-		if err2 := copyFile(tmpFile, fullPath); err2 != nil {
-			return fmt.Errorf("rename fallback failed: %v (orig: %v)", err2, err)
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var ev streamEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			continue // ignore malformed lines rather than aborting the whole stream
 		}
-		_ = os.Remove(tmpFile)
-	}
-	return nil
-}
 
-func moveDirAtomic(tmp string, target string) error {
-	// Try rename
-	if err := os.Rename(tmp, target); err == nil {
-		return nil
-	}
-	// Fallback: copy and remove tmp
-	if err := copyDir(tmp, target); err != nil {
-		return fmt.Errorf("copy fallback failed: %w", err)
+		switch ev.Type {
+		case "file":
+			if staging != "" {
+				if err := writeFileToDisk(d, staging, ev.Path, ev.Content); err != nil {
+					return out, fmt.Errorf("write file %q: %w", ev.Path, err)
+				}
+			}
+			out.Files = append(out.Files, FileOut{Path: ev.Path, Content: ev.Content})
+		case "progress":
+			reporter.Progress(ev.Done, ev.Total, ev.Message)
+		case "warning":
+			reporter.Warning(ev.Text)
+		case "done":
+			out.ProjectName = ev.ProjectName
+			return out, nil
+		}
 	}
-	if err := os.RemoveAll(tmp); err != nil {
-		return fmt.Errorf("remove tmp after copy: %w", err)
+	if err := scanner.Err(); err != nil {
+		return out, fmt.Errorf("read ndjson stream: %w", err)
 	}
-	return nil
+	return out, nil
 }
 
-func copyDir(src string, dst string) error {
-	if err := os.MkdirAll(dst, 0o755); err != nil {
-		return err
-	}
-	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+// resolveDisk turns a projectDir destination (a bare local path or a
+// file://, ftp://, sftp://, s3:// URL) into a Disk plus the path relative
+// to that Disk's root.
+func resolveDisk(projectDir string) (disk.Disk, string, error) {
+	if !strings.Contains(projectDir, "://") {
+		absTarget, err := filepath.Abs(projectDir)
 		if err != nil {
-			return err
+			return nil, "", fmt.Errorf("determine abs path: %w", err)
 		}
-		rel, err := filepath.Rel(src, path)
+		d, err := disk.New(filepath.Dir(absTarget))
 		if err != nil {
-			return err
+			return nil, "", err
 		}
-		targetPath := filepath.Join(dst, rel)
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0o755)
-		}
-		return copyFile(path, targetPath)
-	})
-}
-
-func copyFile(src, dst string) error {
-	in, err := os.Open(src)
+		return d, filepath.ToSlash(filepath.Base(absTarget)), nil
+	}
+	d, err := disk.New(projectDir)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
-	defer in.Close()
+	return d, "", nil
+}
 
-	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
-		return err
+// stagingPath returns a sibling ".tmp-<name>-<rand>" path used to stage
+// writes before the atomic move into target.
+func stagingPath(target string) string {
+	base := filepath.Base(target)
+	if base == "" || base == "." {
+		base = "project"
+	}
+	dir := filepath.Dir(target)
+	name := fmt.Sprintf(".tmp-%s-%d", base, time.Now().UnixNano())
+	if dir == "." || dir == "" {
+		return name
 	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
 
-	out, err := os.Create(dst)
-	if err != nil {
-		return err
+// writeFileToDisk sanitizes relPath and writes content under root on d.
+func writeFileToDisk(d disk.Disk, root string, relPath string, content string) error {
+	if filepath.IsAbs(relPath) {
+		return cerrors.WithStackIf(fmt.Errorf("%w: absolute path not allowed: %s", cerrors.ErrPathTraversal, relPath))
+	}
+	clean := filepath.ToSlash(filepath.Clean(relPath))
+	if clean == ".." || strings.HasPrefix(clean, "../") || strings.Contains(clean, "/../") {
+		return cerrors.WithStackIf(fmt.Errorf("%w: %s", cerrors.ErrPathTraversal, relPath))
 	}
-	defer func() { _ = out.Close() }()
 
-	if _, err := io.Copy(out, in); err != nil {
-		return err
+	fullPath := root + "/" + clean
+	if root == "" {
+		fullPath = clean
 	}
-	if fi, err := in.Stat(); err == nil {
-		_ = out.Chmod(fi.Mode())
+	if err := d.MkdirAll(filepath.ToSlash(filepath.Dir(fullPath))); err != nil {
+		return fmt.Errorf("mkdirall: %w", err)
 	}
-	return nil
+	return d.Write(fullPath, strings.NewReader(content))
 }
 
-
 func WriteFilesAtomically(files []FileOut, projectDir string) error {
-	absTarget, err := filepath.Abs(projectDir)
+	d, targetPath, err := resolveDisk(projectDir)
 	if err != nil {
 		return err
 	}
-	if exists(absTarget) {
-		return &os.PathError{Op: "write", Path: absTarget, Err: os.ErrExist}
-	}
+	defer d.Close()
 
-	parent := filepath.Dir(absTarget)
-	tmp, err := os.MkdirTemp(parent, ".tmp-"+filepath.Base(absTarget)+"-")
+	targetExists, err := d.Exists(targetPath)
 	if err != nil {
 		return err
 	}
-	// cleanup if any error and target does not exist
-	cleanup := func() {
-		_ = os.RemoveAll(tmp)
+	if targetExists {
+		return &os.PathError{Op: "write", Path: projectDir, Err: os.ErrExist}
+	}
+
+	staging := stagingPath(targetPath)
+	if err := d.MkdirAll(staging); err != nil {
+		return err
 	}
+	cleanup := func() { _ = d.RemoveAll(staging) }
 	defer func() {
-		if !exists(absTarget) {
+		if ok, _ := d.Exists(targetPath); !ok {
 			cleanup()
 		}
 	}()
 
-	// write files to tmp
 	for _, f := range files {
-		if err := writeFileToDir(tmp, f.Path, f.Content); err != nil {
+		if err := writeFileToDisk(d, staging, f.Path, f.Content); err != nil {
 			return err
 		}
 	}
 
-	// try rename (atomic if same FS)
-	if err := os.Rename(tmp, absTarget); err == nil {
-		return nil
-	}
-	// fallback: copy recursively then remove tmp
-	if err := copyDir(tmp, absTarget); err != nil {
-		return err
-	}
-	if err := os.RemoveAll(tmp); err != nil {
-		return err
-	}
-	return nil
-}
\ No newline at end of file
+	return disk.MoveAtomic(d, staging, targetPath)
+}
@@ -0,0 +1,108 @@
+package scaffold
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"storyblok-cli-ai/internal/scaffold/disk"
+)
+
+// fakeReporter records every Progress/Warning call it receives, so tests
+// can assert on them instead of scraping stderr output.
+type fakeReporter struct {
+	progress []string
+	warnings []string
+}
+
+func (f *fakeReporter) Progress(done, total int, message string) {
+	f.progress = append(f.progress, message)
+}
+
+func (f *fakeReporter) Warning(text string) {
+	f.warnings = append(f.warnings, text)
+}
+
+func TestStreamGenerateResponse_WritesFilesAndReportsProgress(t *testing.T) {
+	d, err := disk.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("disk.New: %v", err)
+	}
+	defer d.Close()
+
+	body := strings.NewReader(strings.Join([]string{
+		`{"type":"progress","done":1,"total":2,"message":"generating..."}`,
+		`{"type":"file","path":"src/index.ts","content":"console.log(1)"}`,
+		`{"type":"warning","text":"deprecated dependency"}`,
+		`{"type":"done","project_name":"demo"}`,
+	}, "\n"))
+
+	rep := &fakeReporter{}
+	out, err := streamGenerateResponse(context.Background(), body, d, "staging", rep)
+	if err != nil {
+		t.Fatalf("streamGenerateResponse: %v", err)
+	}
+
+	if out.ProjectName != "demo" {
+		t.Errorf("ProjectName = %q, want %q", out.ProjectName, "demo")
+	}
+	if len(out.Files) != 1 || out.Files[0].Path != "src/index.ts" {
+		t.Errorf("Files = %+v, want a single src/index.ts entry", out.Files)
+	}
+	if len(rep.progress) != 1 || rep.progress[0] != "generating..." {
+		t.Errorf("progress = %v, want [\"generating...\"]", rep.progress)
+	}
+	if len(rep.warnings) != 1 || rep.warnings[0] != "deprecated dependency" {
+		t.Errorf("warnings = %v, want [\"deprecated dependency\"]", rep.warnings)
+	}
+
+	ok, err := d.Exists("staging/src/index.ts")
+	if err != nil {
+		t.Fatalf("Exists: %v", err)
+	}
+	if !ok {
+		t.Error("expected the \"file\" event's content to be written under staging")
+	}
+}
+
+func TestStreamGenerateResponse_CancelStopsBeforeDone(t *testing.T) {
+	d, err := disk.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("disk.New: %v", err)
+	}
+	defer d.Close()
+
+	body := strings.NewReader(`{"type":"file","path":"a.txt","content":"x"}` + "\n" + `{"type":"done"}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = streamGenerateResponse(ctx, body, d, "staging", &fakeReporter{})
+	if err == nil {
+		t.Fatal("streamGenerateResponse with an already-canceled context returned nil error, want ctx.Err()")
+	}
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestStreamGenerateResponse_SkipsMalformedLines(t *testing.T) {
+	d, err := disk.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("disk.New: %v", err)
+	}
+	defer d.Close()
+
+	body := strings.NewReader(strings.Join([]string{
+		"not json",
+		`{"type":"done","project_name":"demo"}`,
+	}, "\n"))
+
+	out, err := streamGenerateResponse(context.Background(), body, d, "staging", &fakeReporter{})
+	if err != nil {
+		t.Fatalf("streamGenerateResponse: %v", err)
+	}
+	if out.ProjectName != "demo" {
+		t.Errorf("ProjectName = %q, want %q", out.ProjectName, "demo")
+	}
+}
@@ -0,0 +1,208 @@
+package scaffold
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"storyblok-cli-ai/internal/scaffold/disk"
+)
+
+// manifestFileName is written to the root of every scaffolded project so
+// later re-scaffolds can tell which files are unmodified generator output
+// versus files the user has since edited by hand.
+const manifestFileName = ".storyblok-scaffold.json"
+
+type scaffoldManifest struct {
+	// Checksums maps each file's repo-relative path to the sha256 of the
+	// content that was generated for it.
+	Checksums map[string]string `json:"checksums"`
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func loadManifest(d disk.Disk, targetPath string) (scaffoldManifest, error) {
+	empty := scaffoldManifest{Checksums: map[string]string{}}
+	path := joinDiskPath(targetPath, manifestFileName)
+	ok, err := d.Exists(path)
+	if err != nil || !ok {
+		return empty, nil
+	}
+	b, err := readDiskFile(d, path)
+	if err != nil {
+		return empty, nil
+	}
+	var m scaffoldManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return empty, nil
+	}
+	if m.Checksums == nil {
+		m.Checksums = map[string]string{}
+	}
+	return m, nil
+}
+
+func writeManifest(d disk.Disk, targetPath string, files []FileOut) error {
+	m := scaffoldManifest{Checksums: map[string]string{}}
+	for _, f := range files {
+		m.Checksums[f.Path] = checksum(f.Content)
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return d.Write(joinDiskPath(targetPath, manifestFileName), strings.NewReader(string(b)))
+}
+
+// reconcileScaffold applies newFiles against an already-existing targetPath
+// according to mode. Every file is first staged, then moved into place
+// one at a time so a single failing file doesn't corrupt the rest of an
+// otherwise-successful re-scaffold.
+func reconcileScaffold(d disk.Disk, targetPath string, newFiles []FileOut, mode Mode, force bool) error {
+	manifest, err := loadManifest(d, targetPath)
+	if err != nil {
+		return fmt.Errorf("load scaffold manifest: %w", err)
+	}
+
+	staging := stagingPath(targetPath)
+	if err := d.MkdirAll(staging); err != nil {
+		return fmt.Errorf("create staging dir: %w", err)
+	}
+	defer func() { _ = d.RemoveAll(staging) }()
+
+	applied := scaffoldManifest{Checksums: map[string]string{}}
+	skipped := 0
+
+	for _, f := range newFiles {
+		destPath := joinDiskPath(targetPath, f.Path)
+		onDisk, err := d.Exists(destPath)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", f.Path, err)
+		}
+
+		if mode == ModeDiff {
+			printFileDiff(d, destPath, f)
+			continue
+		}
+
+		if mode == ModeMerge && onDisk {
+			skipped++
+			continue
+		}
+
+		if mode == ModeOverwrite && onDisk && !force {
+			if prevSum, tracked := manifest.Checksums[f.Path]; tracked {
+				if curBytes, rerr := readDiskFile(d, destPath); rerr == nil && checksum(string(curBytes)) != prevSum {
+					fmt.Printf("skipping %s: modified locally since last scaffold (use --force to overwrite)\n", f.Path)
+					skipped++
+					continue
+				}
+			}
+		}
+
+		if err := writeFileToDisk(d, staging, f.Path, f.Content); err != nil {
+			return fmt.Errorf("stage %s: %w", f.Path, err)
+		}
+		stagedPath := joinDiskPath(staging, f.Path)
+		if err := d.MkdirAll(joinDiskPath(targetPath, parentDir(f.Path))); err != nil {
+			return fmt.Errorf("mkdirall for %s: %w", f.Path, err)
+		}
+		if err := d.Rename(stagedPath, destPath); err != nil {
+			return fmt.Errorf("apply %s: %w", f.Path, err)
+		}
+		applied.Checksums[f.Path] = checksum(f.Content)
+	}
+
+	if mode == ModeDiff {
+		return nil
+	}
+
+	// Keep previously tracked checksums for files this round didn't touch
+	// (e.g. merge-skipped) so a later overwrite run still has a baseline.
+	for path, sum := range manifest.Checksums {
+		if _, ok := applied.Checksums[path]; !ok {
+			applied.Checksums[path] = sum
+		}
+	}
+	b, err := json.MarshalIndent(applied, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := d.Write(joinDiskPath(targetPath, manifestFileName), strings.NewReader(string(b))); err != nil {
+		return fmt.Errorf("write scaffold manifest: %w", err)
+	}
+
+	fmt.Printf("Re-scaffolded %s: %d file(s) applied, %d skipped.\n", targetPath, len(newFiles)-skipped, skipped)
+	return nil
+}
+
+func printFileDiff(d disk.Disk, destPath string, f FileOut) {
+	existing := ""
+	if b, err := readDiskFile(d, destPath); err == nil {
+		existing = string(b)
+	}
+	if existing == f.Content {
+		return
+	}
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(existing),
+		B:        difflib.SplitLines(f.Content),
+		FromFile: f.Path,
+		ToFile:   f.Path + " (generated)",
+		Context:  3,
+	}
+	text, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		fmt.Printf("diff %s: %v\n", f.Path, err)
+		return
+	}
+	if text == "" {
+		return
+	}
+	fmt.Print(text)
+}
+
+func readDiskFile(d disk.Disk, path string) ([]byte, error) {
+	r, ok := d.(disk.Reader)
+	if !ok {
+		return nil, fmt.Errorf("disk backend does not support reading files back")
+	}
+	rc, err := r.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func joinDiskPath(parts ...string) string {
+	out := ""
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		if out == "" {
+			out = p
+			continue
+		}
+		out = out + "/" + p
+	}
+	return out
+}
+
+func parentDir(relPath string) string {
+	for i := len(relPath) - 1; i >= 0; i-- {
+		if relPath[i] == '/' {
+			return relPath[:i]
+		}
+	}
+	return ""
+}
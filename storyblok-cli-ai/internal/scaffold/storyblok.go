@@ -1,41 +1,214 @@
 package scaffold
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+
+	cerrors "storyblok-cli-ai/internal/errors"
 )
 
-// EnsureStoryblokCLI checks if the Storyblok CLI is installed, and installs it if user agrees.
+// pinnedStoryblokCLIVersion is the version of the Storyblok CLI this build
+// was tested against. DependencyManager fails when an installed CLI
+// reports a different version, unless AllowVersionMismatch is set, in
+// which case it only warns.
+const pinnedStoryblokCLIVersion = "4.2.0"
+
+// packageManagerEnvVar lets users pin the package manager DependencyManager
+// uses, bypassing PATH auto-detection (e.g. in CI images with several
+// installed).
+const packageManagerEnvVar = "STORYBLOK_CLI_PKG_MANAGER"
+
+// DependencyManager installs and verifies the Storyblok CLI without
+// assuming npm, an interactive stdin, or a writable global install
+// location — the constraints that made the old EnsureStoryblokCLI unusable
+// in CI/Docker/Windows.
+type DependencyManager struct {
+	// In/Out drive the confirmation prompt; default to os.Stdin/os.Stdout.
+	In  io.Reader
+	Out io.Writer
+
+	// AssumeYes skips the confirmation prompt (wired to --yes/--assume-yes).
+	AssumeYes bool
+
+	// PackageManager overrides auto-detection when non-empty.
+	PackageManager string
+
+	// AllowVersionMismatch downgrades a pinnedStoryblokCLIVersion mismatch
+	// from a hard failure to a warning, for users who intentionally pinned
+	// a different CLI version themselves.
+	AllowVersionMismatch bool
+}
+
+// NewDependencyManager returns a DependencyManager wired to the real
+// terminal.
+func NewDependencyManager() *DependencyManager {
+	return &DependencyManager{In: os.Stdin, Out: os.Stdout}
+}
+
+// EnsureStoryblokCLI checks if the Storyblok CLI is installed, and installs
+// it if the user agrees. It's a thin, interactive-only wrapper around
+// DependencyManager kept for callers that don't need --yes or a pinned
+// package manager.
 func EnsureStoryblokCLI() error {
-	// Check if "storyblok" is already available
-	_, err := exec.LookPath("storyblok")
-	if err == nil {
-		return nil // Already installed
+	return NewDependencyManager().Ensure()
+}
+
+// Ensure makes sure the Storyblok CLI is on PATH, installing it (with the
+// detected or configured package manager) if it isn't, and fails (or, with
+// AllowVersionMismatch, warns) if the installed version doesn't match
+// pinnedStoryblokCLIVersion.
+func (m *DependencyManager) Ensure() error {
+	if path, err := exec.LookPath("storyblok"); err == nil {
+		return m.verifyVersion(path)
 	}
 
-	// Prompt user
-	fmt.Print("Storyblok CLI not found. Install it now with `npm install -g storyblok@beta`? (Y/n): ")
-	var resp string
-	fmt.Scanln(&resp)
-	if resp != "" && (resp[0] == 'n' || resp[0] == 'N') {
-		return fmt.Errorf("Storyblok CLI is required but not installed")
+	if !m.AssumeYes {
+		ok, err := m.confirm("Storyblok CLI not found. Install it now? (Y/n): ")
+		if err != nil {
+			return cerrors.WithStackIf(err)
+		}
+		if !ok {
+			return cerrors.WithStackIf(fmt.Errorf("%w: storyblok CLI", cerrors.ErrDependencyMissing))
+		}
 	}
 
-	// Run installation
-	cmd := exec.Command("npm", "install", "-g", "storyblok@beta")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to install Storyblok CLI: %w", err)
+	pm := m.detectPackageManager()
+	if err := m.runInstall(pm, true, ""); err != nil {
+		home, herr := os.UserHomeDir()
+		if herr != nil {
+			return cerrors.WithStackIf(fmt.Errorf("install storyblok CLI globally: %w", err))
+		}
+		prefix := filepath.Join(home, ".storyblok-cli-ai")
+		fmt.Fprintf(m.out(), "global install failed (%v); retrying as a per-user install at %s\n", err, prefix)
+		if ierr := m.runInstall(pm, false, prefix); ierr != nil {
+			return cerrors.WithStackIf(fmt.Errorf("install storyblok CLI: %w (global attempt: %v)", ierr, err))
+		}
+		binDir := filepath.Join(prefix, "node_modules", ".bin")
+		if perr := os.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH")); perr != nil {
+			return cerrors.WithStackIf(fmt.Errorf("update PATH for per-user install: %w", perr))
+		}
 	}
 
-	// Verify installation succeeded
-	_, err = exec.LookPath("storyblok")
+	path, err := exec.LookPath("storyblok")
 	if err != nil {
-		return fmt.Errorf("Storyblok CLI installation did not succeed")
+		return cerrors.WithStackIf(fmt.Errorf("%w: storyblok CLI (install appears to have failed)", cerrors.ErrDependencyMissing))
+	}
+	fmt.Fprintln(m.out(), "✅ Storyblok CLI installed successfully.")
+	return m.verifyVersion(path)
+}
+
+// detectPackageManager honors an explicit override, then
+// STORYBLOK_CLI_PKG_MANAGER, then the first of pnpm/yarn/bun/npm found on
+// PATH, falling back to npm.
+func (m *DependencyManager) detectPackageManager() string {
+	if m.PackageManager != "" {
+		return m.PackageManager
+	}
+	if env := strings.TrimSpace(os.Getenv(packageManagerEnvVar)); env != "" {
+		return env
+	}
+	for _, candidate := range []string{"pnpm", "yarn", "bun", "npm"} {
+		if _, err := exec.LookPath(candidate); err == nil {
+			return candidate
+		}
+	}
+	return "npm"
+}
+
+// runInstall shells out to pm to install the pinned Storyblok CLI, either
+// globally or under prefix (a per-user install directory).
+func (m *DependencyManager) runInstall(pm string, global bool, prefix string) error {
+	args := installArgs(pm, global, prefix)
+	cmd := exec.Command(pm, args...)
+	cmd.Stdout = m.out()
+	cmd.Stderr = m.out()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s: %w", pm, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// installArgs returns the install command for pm. Per-user installs use
+// each manager's local (non-global) install with a custom prefix, so a
+// failed global install (common on locked-down CI runners) still leaves a
+// working CLI on PATH once the caller prepends prefix/node_modules/.bin.
+func installArgs(pm string, global bool, prefix string) []string {
+	pkg := "storyblok@" + pinnedStoryblokCLIVersion
+	switch pm {
+	case "pnpm":
+		if global {
+			return []string{"add", "-g", pkg}
+		}
+		return []string{"add", "--prefix", prefix, pkg}
+	case "yarn":
+		if global {
+			return []string{"global", "add", pkg}
+		}
+		return []string{"add", "--modules-folder", filepath.Join(prefix, "node_modules"), pkg}
+	case "bun":
+		if global {
+			return []string{"add", "-g", pkg}
+		}
+		return []string{"add", "--cwd", prefix, pkg}
+	default: // npm
+		if global {
+			return []string{"install", "-g", pkg}
+		}
+		return []string{"install", "--prefix", prefix, pkg}
 	}
+}
 
-	fmt.Println("✅ Storyblok CLI installed successfully.")
+// verifyVersion runs `storyblok --version` and, unless AllowVersionMismatch
+// is set, refuses to proceed if it doesn't match pinnedStoryblokCLIVersion —
+// a silently mismatched CLI has burned us before with schema drift that
+// only showed up mid-scaffold.
+func (m *DependencyManager) verifyVersion(path string) error {
+	out, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return cerrors.WithStackIf(fmt.Errorf("check storyblok --version: %w", err))
+	}
+	version := strings.TrimSpace(string(out))
+	if version != "" && !strings.Contains(version, pinnedStoryblokCLIVersion) {
+		if m.AllowVersionMismatch {
+			fmt.Fprintf(m.out(), "warning: storyblok CLI reports version %q, this build was tested against %s\n", version, pinnedStoryblokCLIVersion)
+			return nil
+		}
+		return cerrors.WithStackIf(fmt.Errorf("%w: storyblok CLI reports version %q, this build was tested against %s (set AllowVersionMismatch to proceed anyway)", cerrors.ErrVersionMismatch, version, pinnedStoryblokCLIVersion))
+	}
 	return nil
 }
+
+// confirm prints prompt to Out and reads a yes/no answer from In. An empty
+// answer (bare Enter) counts as yes, matching the CLI's original (Y/n)
+// prompt.
+func (m *DependencyManager) confirm(prompt string) (bool, error) {
+	fmt.Fprint(m.out(), prompt)
+	resp, err := bufio.NewReader(m.in()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	resp = strings.TrimSpace(resp)
+	if resp == "" {
+		return true, nil
+	}
+	return resp[0] != 'n' && resp[0] != 'N', nil
+}
+
+func (m *DependencyManager) in() io.Reader {
+	if m.In != nil {
+		return m.In
+	}
+	return os.Stdin
+}
+
+func (m *DependencyManager) out() io.Writer {
+	if m.Out != nil {
+		return m.Out
+	}
+	return os.Stdout
+}
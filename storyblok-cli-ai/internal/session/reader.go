@@ -0,0 +1,83 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Load reads every event from a session's JSONL log, in order.
+func Load(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open session log: %w", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return nil, fmt.Errorf("parse session event: %w", err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read session log: %w", err)
+	}
+	return events, nil
+}
+
+// Answers extracts id -> value from every recorded "answer" event at or
+// after fromRound, for feeding back into a replay prompter. A later
+// answer for the same id overrides an earlier one.
+func Answers(events []Event, fromRound int) map[string]string {
+	out := map[string]string{}
+	for _, ev := range events {
+		if ev.Type != EventAnswer || ev.Round < fromRound {
+			continue
+		}
+		m, ok := ev.Data.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := m["id"].(string)
+		value, _ := m["value"].(string)
+		if id == "" {
+			continue
+		}
+		out[id] = value
+	}
+	return out
+}
+
+// BackendResponses extracts, per backend method ("generate", "questions",
+// "overlay"), the ordered list of recorded backend_response payloads at
+// or after fromRound, for a replay backend.Client to hand back in the
+// same order the original run received them.
+func BackendResponses(events []Event, fromRound int) map[string][]map[string]interface{} {
+	out := map[string][]map[string]interface{}{}
+	for _, ev := range events {
+		if ev.Type != EventBackendResponse || ev.Round < fromRound {
+			continue
+		}
+		m, ok := ev.Data.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		method, _ := m["method"].(string)
+		resp, _ := m["response"].(map[string]interface{})
+		if method == "" || resp == nil {
+			continue
+		}
+		out[method] = append(out[method], resp)
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+// Package session records every prompt, answer, backend call, file
+// write, and error of a create-wizard run into a single append-only
+// JSONL file under ~/.storyblok-ai-cli/sessions/<id>.jsonl, so a user
+// hitting a bug can hand the file to a maintainer who reproduces it with
+// `storyblok-cli-ai replay <session-id>`. The Storyblok token, and any
+// field named via --secret-field, are redacted before being written.
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EventType tags each line of a session log.
+type EventType string
+
+const (
+	EventPrompt          EventType = "prompt"
+	EventAnswer          EventType = "answer"
+	EventBackendRequest  EventType = "backend_request"
+	EventBackendResponse EventType = "backend_response"
+	EventFileWrite       EventType = "file_write"
+	EventOverlayApply    EventType = "overlay_apply"
+	EventError           EventType = "error"
+)
+
+// Event is one line of a session's JSONL log.
+type Event struct {
+	Type      EventType   `json:"type"`
+	Round     int         `json:"round"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Recorder appends Events to a session's JSONL log. A nil *Recorder is
+// valid and every method on it is a no-op, so callers that don't have one
+// (e.g. when opening the session log failed) don't need to guard every
+// call site.
+type Recorder struct {
+	id           string
+	f            *os.File
+	secretFields map[string]bool
+}
+
+// Dir returns ~/.storyblok-ai-cli/sessions, creating it if needed.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("determine home dir: %w", err)
+	}
+	dir := filepath.Join(home, ".storyblok-ai-cli", "sessions")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create sessions dir: %w", err)
+	}
+	return dir, nil
+}
+
+// Path returns the JSONL log path for sessionID.
+func Path(sessionID string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionID+".jsonl"), nil
+}
+
+// New opens (creating if needed) the append-only log for sessionID.
+// secretFields are field ids (matched case-insensitively, in addition to
+// the always-redacted "token") whose values get replaced with
+// "[REDACTED]" wherever they appear in recorded data.
+func New(sessionID string, secretFields []string) (*Recorder, error) {
+	path, err := Path(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open session log: %w", err)
+	}
+	fields := map[string]bool{"token": true}
+	for _, s := range secretFields {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			fields[s] = true
+		}
+	}
+	return &Recorder{id: sessionID, f: f, secretFields: fields}, nil
+}
+
+// ID returns the session ID this recorder was opened with.
+func (r *Recorder) ID() string {
+	if r == nil {
+		return ""
+	}
+	return r.id
+}
+
+// RedactIfSecret returns "[REDACTED]" if id is the token field or was
+// named via --secret-field, and value unchanged otherwise. Use this for
+// scalar prompt answers, which redact() (keyed on map field names) can't
+// see.
+func (r *Recorder) RedactIfSecret(id, value string) string {
+	if r == nil {
+		return value
+	}
+	if r.secretFields[strings.ToLower(id)] {
+		return "[REDACTED]"
+	}
+	return value
+}
+
+// Record appends one event. A write failure is reported to stderr rather
+// than returned, so a logging problem never aborts the wizard.
+func (r *Recorder) Record(t EventType, round int, data interface{}) {
+	if r == nil {
+		return
+	}
+	ev := Event{Type: t, Round: round, Timestamp: time.Now().UTC(), Data: r.redact(data)}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to marshal session event: %v\n", err)
+		return
+	}
+	if _, err := r.f.Write(append(b, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write session event: %v\n", err)
+	}
+}
+
+// Close closes the underlying log file.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	return r.f.Close()
+}
+
+// redact deep-copies data, replacing any map value whose key matches
+// (case-insensitively) "token" or a name from --secret-field with
+// "[REDACTED]". Backend payloads use the field id as the map key (e.g.
+// user_answers["token"]), so this one rule covers requests, responses,
+// and anything else shaped like map[string]interface{}.
+func (r *Recorder) redact(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			if r.secretFields[strings.ToLower(k)] {
+				out[k] = "[REDACTED]"
+				continue
+			}
+			out[k] = r.redact(val)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for k, val := range v {
+			if r.secretFields[strings.ToLower(k)] {
+				out[k] = "[REDACTED]"
+				continue
+			}
+			out[k] = val
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = r.redact(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewRecordAndRedact(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	rec, err := New("test-session", []string{"api_key"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer rec.Close()
+
+	if rec.ID() != "test-session" {
+		t.Errorf("ID() = %q, want %q", rec.ID(), "test-session")
+	}
+
+	rec.Record(EventBackendRequest, 1, map[string]interface{}{
+		"token":   "secret-token",
+		"api_key": "secret-key",
+		"nested": map[string]interface{}{
+			"token": "nested-secret",
+			"other": "keep-me",
+		},
+		"list": []interface{}{
+			map[string]interface{}{"token": "list-secret"},
+		},
+		"plain": "visible",
+	})
+
+	path, err := Path("test-session")
+	if err != nil {
+		t.Fatalf("Path: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	var ev Event
+	line := strings.TrimSpace(string(b))
+	if err := json.Unmarshal([]byte(line), &ev); err != nil {
+		t.Fatalf("unmarshal recorded event: %v\nraw: %s", err, line)
+	}
+	data, ok := ev.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Data = %T, want map[string]interface{}", ev.Data)
+	}
+
+	if data["token"] != "[REDACTED]" {
+		t.Errorf(`data["token"] = %v, want "[REDACTED]"`, data["token"])
+	}
+	if data["api_key"] != "[REDACTED]" {
+		t.Errorf(`data["api_key"] = %v, want "[REDACTED]"`, data["api_key"])
+	}
+	if data["plain"] != "visible" {
+		t.Errorf(`data["plain"] = %v, want "visible"`, data["plain"])
+	}
+	nested, ok := data["nested"].(map[string]interface{})
+	if !ok || nested["token"] != "[REDACTED]" || nested["other"] != "keep-me" {
+		t.Errorf(`data["nested"] = %v, want token redacted and other kept`, data["nested"])
+	}
+	list, ok := data["list"].([]interface{})
+	if !ok || len(list) != 1 {
+		t.Fatalf(`data["list"] = %v, want a single-element list`, data["list"])
+	}
+	listEntry, ok := list[0].(map[string]interface{})
+	if !ok || listEntry["token"] != "[REDACTED]" {
+		t.Errorf(`data["list"][0] = %v, want token redacted`, list[0])
+	}
+}
+
+func TestRedactIfSecret(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	rec, err := New("test-session-2", []string{"Custom-Field"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer rec.Close()
+
+	if got := rec.RedactIfSecret("token", "abc"); got != "[REDACTED]" {
+		t.Errorf(`RedactIfSecret("token", ...) = %q, want "[REDACTED]"`, got)
+	}
+	if got := rec.RedactIfSecret("custom-field", "abc"); got != "[REDACTED]" {
+		t.Errorf(`RedactIfSecret("custom-field", ...) = %q, want "[REDACTED]" (case-insensitive, from --secret-field)`, got)
+	}
+	if got := rec.RedactIfSecret("description", "abc"); got != "abc" {
+		t.Errorf(`RedactIfSecret("description", ...) = %q, want "abc"`, got)
+	}
+}
+
+func TestNilRecorderIsANoop(t *testing.T) {
+	var rec *Recorder
+	rec.Record(EventError, 1, map[string]interface{}{"token": "x"})
+	if got := rec.RedactIfSecret("token", "x"); got != "x" {
+		t.Errorf("nil Recorder RedactIfSecret = %q, want unredacted passthrough", got)
+	}
+	if got := rec.ID(); got != "" {
+		t.Errorf("nil Recorder ID() = %q, want empty", got)
+	}
+	if err := rec.Close(); err != nil {
+		t.Errorf("nil Recorder Close() = %v, want nil", err)
+	}
+}
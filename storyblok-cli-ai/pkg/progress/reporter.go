@@ -0,0 +1,49 @@
+// Package progress defines a small reporter interface so long-running
+// generation steps can surface progress to a terminal without coupling the
+// caller to any particular UI library. Tests can inject a fake Reporter
+// instead of asserting against stderr output.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Reporter receives progress and warning updates during a generation run.
+type Reporter interface {
+	Progress(done, total int, message string)
+	Warning(text string)
+}
+
+// stderrReporter is the default Reporter, printing a single updating line
+// to an io.Writer (stderr in production).
+type stderrReporter struct {
+	out io.Writer
+}
+
+// NewStderrReporter returns the default Reporter, writing to os.Stderr.
+func NewStderrReporter() Reporter {
+	return &stderrReporter{out: os.Stderr}
+}
+
+func (r *stderrReporter) Progress(done, total int, message string) {
+	if total > 0 {
+		fmt.Fprintf(r.out, "\r[%d/%d] %s", done, total, message)
+	} else {
+		fmt.Fprintf(r.out, "\r%s", message)
+	}
+}
+
+func (r *stderrReporter) Warning(text string) {
+	fmt.Fprintf(r.out, "\nwarning: %s\n", text)
+}
+
+// Noop discards every update; useful as a default for callers that don't
+// care about progress (e.g. WriteFilesAtomically callers, tests).
+func Noop() Reporter { return noopReporter{} }
+
+type noopReporter struct{}
+
+func (noopReporter) Progress(done, total int, message string) {}
+func (noopReporter) Warning(text string)                      {}